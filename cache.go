@@ -0,0 +1,94 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+type cursorCacheKey struct {
+	uri    string
+	config string
+}
+
+type cursorCache struct {
+	free    map[cursorCacheKey][]*Cursor
+	formats map[cursorCacheKey]*cursorFormat
+}
+
+// CachedCursor returns an idle cursor previously opened on this session with
+// the same uri and config, or opens a new one. Cursors must be handed back
+// with ReleaseCursor rather than closed.
+func (s *Session) CachedCursor(uri, config string) (*Cursor, error) {
+	if s.cursors == nil {
+		s.cursors = &cursorCache{
+			free:    make(map[cursorCacheKey][]*Cursor),
+			formats: make(map[cursorCacheKey]*cursorFormat),
+		}
+	}
+
+	key := cursorCacheKey{uri: uri, config: config}
+
+	if free := s.cursors.free[key]; len(free) > 0 {
+		c := free[len(free)-1]
+		free[len(free)-1] = nil
+		s.cursors.free[key] = free[:len(free)-1]
+
+		c.inUse = true
+
+		return c, nil
+	}
+
+	c, err := s.openCursor(uri, config, s.cursors.formats[key])
+	if err != nil {
+		return nil, err
+	}
+
+	s.cursors.formats[key] = &cursorFormat{
+		keyPackers:   c.keyPackers,
+		valuePackers: c.valuePackers,
+	}
+
+	c.cacheKey = &key
+	c.inUse = true
+
+	return c, nil
+}
+
+func (s *Session) ReleaseCursor(c *Cursor) error {
+	if c.cacheKey == nil || c.session != s || s.cursors == nil {
+		return fmt.Errorf("cursor on %s was not opened by CachedCursor on this session", c.URI())
+	}
+
+	if !c.inUse {
+		return fmt.Errorf("cursor on %s was already released", c.URI())
+	}
+
+	if err := c.Reset(); err != nil {
+		return fmt.Errorf("reset cursor: %w", err)
+	}
+
+	c.inUse = false
+	s.cursors.free[*c.cacheKey] = append(s.cursors.free[*c.cacheKey], c)
+
+	return nil
+}
+
+func (s *Session) closeCachedCursors() error {
+	if s.cursors == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for _, free := range s.cursors.free {
+		for _, c := range free {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	s.cursors = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,152 @@
+package wtgo_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCachedCursor(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := insert(env.cursor, "a", "1"); err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	first, err := env.session.CachedCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("cached cursor: %s", err)
+	}
+
+	r, err := searchKey[string, string](first, "a")
+	if err != nil {
+		t.Fatalf("search key: %s", err)
+	}
+
+	if diff := cmp.Diff("1", r.Value); diff != "" {
+		t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+	}
+
+	second, err := env.session.CachedCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("second cached cursor: %s", err)
+	}
+
+	if first == second {
+		t.Fatalf("cursor in use was handed out twice")
+	}
+
+	if err := env.session.ReleaseCursor(first); err != nil {
+		t.Fatalf("release cursor: %s", err)
+	}
+
+	reused, err := env.session.CachedCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("reused cached cursor: %s", err)
+	}
+
+	if reused != first {
+		t.Fatalf("released cursor was not reused")
+	}
+
+	if err := env.session.ReleaseCursor(second); err != nil {
+		t.Fatalf("release second cursor: %s", err)
+	}
+
+	if err := env.session.ReleaseCursor(second); err == nil {
+		t.Fatalf("expected releasing a cursor twice to fail")
+	}
+
+	again, err := env.session.CachedCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("cached cursor after double release: %s", err)
+	}
+
+	next, err := env.session.CachedCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("cached cursor after double release: %s", err)
+	}
+
+	if again == next {
+		t.Fatalf("cursor released twice was handed out twice")
+	}
+
+	other, err := env.session.CachedCursor(tablename, "raw")
+	if err != nil {
+		t.Fatalf("cached cursor with other config: %s", err)
+	}
+
+	if other == first || other == again || other == next {
+		t.Fatalf("cursor handed out for a different config")
+	}
+
+	if err := env.session.ReleaseCursor(env.cursor); err == nil {
+		t.Fatalf("expected releasing an uncached cursor to fail")
+	}
+
+	if err := env.session.ReleaseCursor(reused); err != nil {
+		t.Fatalf("release reused cursor: %s", err)
+	}
+
+	if err := env.session.ReleaseCursor(again); err != nil {
+		t.Fatalf("release cursor: %s", err)
+	}
+
+	if err := env.session.ReleaseCursor(next); err != nil {
+		t.Fatalf("release cursor: %s", err)
+	}
+
+	if err := env.session.ReleaseCursor(other); err != nil {
+		t.Fatalf("release other cursor: %s", err)
+	}
+
+	if err := env.session.Close(""); err != nil {
+		t.Fatalf("close session: %s", err)
+	}
+}
+
+func BenchmarkOpenCursor(b *testing.B) {
+	tablename := "table:test-table"
+	tableconf := "key_format=SQ,value_format=SQQS"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		b.Fatalf("new table cursor test env: %s", err)
+	}
+
+	b.Cleanup(func() { env.Close() })
+
+	b.Run("open-close", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c, err := env.session.OpenCursor(tablename, "")
+			if err != nil {
+				b.Fatalf("open cursor: %s", err)
+			}
+
+			if err := c.Close(); err != nil {
+				b.Fatalf("close cursor: %s", err)
+			}
+		}
+	})
+
+	b.Run("cached-release", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c, err := env.session.CachedCursor(tablename, "")
+			if err != nil {
+				b.Fatalf("cached cursor: %s", err)
+			}
+
+			if err := env.session.ReleaseCursor(c); err != nil {
+				b.Fatalf("release cursor: %s", err)
+			}
+		}
+	})
+}
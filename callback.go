@@ -0,0 +1,47 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+// callbackCode converts the result of a Go callback into the return code
+// WiredTiger expects. WiredTiger error codes and errnos wrapped in err are
+// passed through, the fs package's errors become the matching errno, and
+// anything else becomes ErrError.
+func callbackCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var code ErrorCode
+	if errors.As(err, &code) {
+		return int(code)
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return int(errno)
+	}
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return int(syscall.ENOENT)
+	case errors.Is(err, fs.ErrExist):
+		return int(syscall.EEXIST)
+	case errors.Is(err, fs.ErrPermission):
+		return int(syscall.EACCES)
+	}
+
+	return int(ErrError)
+}
+
+// recoverCallback stops a panic in a Go callback from unwinding into
+// WiredTiger's C stack and reports it as an error instead.
+func recoverCallback(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("callback panic: %v", r)
+	}
+}
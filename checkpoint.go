@@ -0,0 +1,266 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtconfig"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	ErrCheckpointBusy     = errors.New("checkpoint in use")
+)
+
+// WiredTiger names its own checkpoints with this prefix; they are not
+// returned by ListCheckpoints and cannot be created or dropped by name.
+const internalCheckpointPrefix = "WiredTigerCheckpoint"
+
+type CheckpointInfo struct {
+	Name  string
+	Order int64
+	Time  time.Time
+	Size  int64
+}
+
+func (s *Session) CreateCheckpoint(name, config string) error {
+	if err := checkCheckpointName(name); err != nil {
+		return err
+	}
+
+	c := "name=" + name
+	if config != "" {
+		c += "," + config
+	}
+
+	if err := s.Checkpoint(c); err != nil {
+		return fmt.Errorf("checkpoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (s *Session) DropCheckpoint(name string) error {
+	if err := checkCheckpointName(name); err != nil {
+		return err
+	}
+
+	checkpoints, err := s.ListCheckpoints("")
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(checkpoints, func(c CheckpointInfo) bool { return c.Name == name }) {
+		return fmt.Errorf("checkpoint %s: %w", name, ErrCheckpointNotFound)
+	}
+
+	if err := s.Checkpoint("drop=(" + name + ")"); err != nil {
+		if errors.Is(err, ErrorCode(syscall.EBUSY)) {
+			return fmt.Errorf("checkpoint %s: %w", name, ErrCheckpointBusy)
+		}
+
+		return fmt.Errorf("drop checkpoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (s *Session) OpenCheckpointCursor(uri, name, config string) (*Cursor, error) {
+	if err := checkCheckpointName(name); err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := s.ListCheckpoints(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(checkpoints, func(c CheckpointInfo) bool { return c.Name == name }) {
+		return nil, fmt.Errorf("checkpoint %s of %s: %w", name, uri, ErrCheckpointNotFound)
+	}
+
+	c := "checkpoint=" + name
+	if config != "" {
+		c += "," + config
+	}
+
+	cursor, err := s.OpenCursor(uri, c)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrorCode(syscall.ENOENT)) {
+			return nil, fmt.Errorf("checkpoint %s of %s: %w", name, uri, ErrCheckpointNotFound)
+		}
+
+		return nil, err
+	}
+
+	return cursor, nil
+}
+
+// ListCheckpoints returns the named checkpoints of the file backing uri,
+// ordered oldest first. An empty uri lists checkpoints across all files.
+func (s *Session) ListCheckpoints(uri string) ([]CheckpointInfo, error) {
+	var files []string
+
+	if uri == "" {
+		f, err := s.metadataKeys("file:")
+		if err != nil {
+			return nil, err
+		}
+
+		files = f
+	} else {
+		f, err := s.sourceFile(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		files = []string{f}
+	}
+
+	byName := make(map[string]CheckpointInfo)
+
+	for _, file := range files {
+		config, err := s.Metadata(file)
+		if err != nil {
+			return nil, err
+		}
+
+		checkpoints, err := parseCheckpoints(config)
+		if err != nil {
+			return nil, fmt.Errorf("parse checkpoints of %s: %w", file, err)
+		}
+
+		for _, c := range checkpoints {
+			if prev, ok := byName[c.Name]; !ok || c.Time.After(prev.Time) {
+				byName[c.Name] = c
+			}
+		}
+	}
+
+	checkpoints := make([]CheckpointInfo, 0, len(byName))
+
+	for _, c := range byName {
+		checkpoints = append(checkpoints, c)
+	}
+
+	slices.SortFunc(checkpoints, func(a, b CheckpointInfo) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return checkpoints, nil
+}
+
+func (s *Session) sourceFile(uri string) (string, error) {
+	switch {
+	case strings.HasPrefix(uri, "file:"):
+		return uri, nil
+	case strings.HasPrefix(uri, "table:"):
+		config, err := s.Metadata(uri)
+		if err != nil {
+			return "", err
+		}
+
+		value, _, err := wtconfig.Get(config, "colgroups")
+		if err != nil {
+			return "", fmt.Errorf("parse metadata of %s: %w", uri, err)
+		}
+
+		colgroups, err := wtconfig.List(value)
+		if err != nil {
+			return "", fmt.Errorf("parse colgroups of %s: %w", uri, err)
+		}
+
+		colgroup := "colgroup:" + strings.TrimPrefix(uri, "table:")
+		if len(colgroups) > 0 {
+			colgroup += ":" + colgroups[0]
+		}
+
+		return s.sourceFile(colgroup)
+	case strings.HasPrefix(uri, "colgroup:"), strings.HasPrefix(uri, "index:"):
+		config, err := s.Metadata(uri)
+		if err != nil {
+			return "", err
+		}
+
+		source, ok, err := wtconfig.Get(config, "source")
+		if err != nil {
+			return "", fmt.Errorf("parse metadata of %s: %w", uri, err)
+		}
+
+		if !ok {
+			return "", fmt.Errorf("%s has no source", uri)
+		}
+
+		return s.sourceFile(source)
+	default:
+		return "", fmt.Errorf("cannot find the file backing %s", uri)
+	}
+}
+
+func parseCheckpoints(config string) ([]CheckpointInfo, error) {
+	value, ok, err := wtconfig.Get(config, "checkpoint")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	pairs, err := wtconfig.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]CheckpointInfo, 0, len(pairs))
+
+	for _, p := range pairs {
+		if strings.HasPrefix(p.Key, internalCheckpointPrefix) {
+			continue
+		}
+
+		fields, err := wtconfig.Parse(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint %s: %w", p.Key, err)
+		}
+
+		c := CheckpointInfo{Name: p.Key}
+
+		for _, f := range fields {
+			var n int64
+
+			switch f.Key {
+			case "order", "time", "size":
+				n, err = strconv.ParseInt(f.Value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("checkpoint %s %s: %w", p.Key, f.Key, err)
+				}
+			}
+
+			switch f.Key {
+			case "order":
+				c.Order = n
+			case "time":
+				c.Time = time.Unix(n, 0)
+			case "size":
+				c.Size = n
+			}
+		}
+
+		checkpoints = append(checkpoints, c)
+	}
+
+	return checkpoints, nil
+}
+
+func checkCheckpointName(name string) error {
+	if name == "" || strings.HasPrefix(name, internalCheckpointPrefix) || strings.ContainsAny(name, ",=()\"") {
+		return fmt.Errorf("invalid checkpoint name '%s'", name)
+	}
+
+	return nil
+}
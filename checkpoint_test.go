@@ -0,0 +1,109 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNamedCheckpoints(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := insert(env.cursor, "a", "1"); err != nil {
+		t.Fatalf("insert before checkpoint: %s", err)
+	}
+
+	if err := env.cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	if err := env.session.CreateCheckpoint("nightly", ""); err != nil {
+		t.Fatalf("create checkpoint: %s", err)
+	}
+
+	if err := insert(env.cursor, "b", "2"); err != nil {
+		t.Fatalf("insert after checkpoint: %s", err)
+	}
+
+	if err := env.cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	checkpoints, err := env.session.ListCheckpoints(tablename)
+	if err != nil {
+		t.Fatalf("list checkpoints: %s", err)
+	}
+
+	names := make([]string, 0, len(checkpoints))
+	for _, c := range checkpoints {
+		names = append(names, c.Name)
+	}
+
+	if diff := cmp.Diff([]string{"nightly"}, names); diff != "" {
+		t.Fatalf("checkpoints don't match (-want +got):\n%s", diff)
+	}
+
+	cursor, err := env.session.OpenCheckpointCursor(tablename, "nightly", "")
+	if err != nil {
+		t.Fatalf("open checkpoint cursor: %s", err)
+	}
+
+	keys := make([]string, 0, 2)
+
+	for cursor.Next() {
+		var k string
+
+		if err := cursor.GetKey(&k); err != nil {
+			t.Fatalf("get key: %s", err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"a"}, keys); diff != "" {
+		t.Fatalf("checkpoint keys don't match (-want +got):\n%s", diff)
+	}
+
+	if _, err := env.session.OpenCheckpointCursor(tablename, "weekly", ""); !errors.Is(err, wtgo.ErrCheckpointNotFound) {
+		t.Fatalf("open missing checkpoint returned err '%v', expected checkpoint not found", err)
+	}
+
+	if err := env.session.DropCheckpoint("nightly"); !errors.Is(err, wtgo.ErrCheckpointBusy) {
+		t.Fatalf("drop open checkpoint returned err '%v', expected checkpoint in use", err)
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("close checkpoint cursor: %s", err)
+	}
+
+	if err := env.session.DropCheckpoint("nightly"); err != nil {
+		t.Fatalf("drop checkpoint: %s", err)
+	}
+
+	if err := env.session.DropCheckpoint("nightly"); !errors.Is(err, wtgo.ErrCheckpointNotFound) {
+		t.Fatalf("drop missing checkpoint returned err '%v', expected checkpoint not found", err)
+	}
+
+	checkpoints, err = env.session.ListCheckpoints("")
+	if err != nil {
+		t.Fatalf("list checkpoints after drop: %s", err)
+	}
+
+	if len(checkpoints) != 0 {
+		t.Fatalf("expected no checkpoints after drop, got %v", checkpoints)
+	}
+}
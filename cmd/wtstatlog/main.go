@@ -0,0 +1,117 @@
+// Command wtstatlog summarizes the worst cache, eviction and checkpoint
+// intervals of JSON statistics logs.
+//
+//	wtstatlog [-n 5] WiredTigerStat.*
+//
+// It reads standard input when no files are named.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/dylrich/wtgo/statlog"
+	"io"
+	"os"
+	"slices"
+	"time"
+)
+
+type watch struct {
+	category string
+
+	// names lists the description of the statistic in different
+	// WiredTiger versions.
+	names []string
+
+	// counter statistics are ranked by how much they grew in an interval,
+	// the others by their value.
+	counter bool
+}
+
+var watches = []watch{
+	{category: "cache", names: []string{"cache: bytes currently in the cache"}},
+	{category: "cache", names: []string{"cache: tracked dirty bytes in the cache"}},
+	{category: "eviction", names: []string{"cache: pages evicted by application threads", "eviction: pages evicted by application threads"}, counter: true},
+	{category: "eviction", names: []string{"cache: eviction server unable to reach eviction goal", "eviction: eviction server unable to reach eviction goal"}, counter: true},
+	{category: "checkpoint", names: []string{"transaction: transaction checkpoint most recent time (msecs)", "checkpoint: most recent time (msecs)"}},
+	{category: "checkpoint", names: []string{"transaction: transaction checkpoint total time (msecs)", "checkpoint: total time (msecs)"}, counter: true},
+}
+
+func main() {
+	n := flag.Int("n", 5, "number of intervals to print per statistic")
+	flag.Parse()
+
+	var l *statlog.Log
+	var err error
+
+	if flag.NArg() == 0 {
+		l, err = statlog.Read(os.Stdin)
+	} else {
+		l, err = statlog.ReadFiles(flag.Args()...)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wtstatlog: %s\n", err)
+		os.Exit(1)
+	}
+
+	summarize(os.Stdout, l, *n)
+}
+
+func summarize(w io.Writer, l *statlog.Log, n int) {
+	category := ""
+
+	for _, watch := range watches {
+		var series *statlog.Series
+
+		for _, name := range watch.names {
+			if s, ok := l.Get("", name); ok {
+				series = s
+				break
+			}
+		}
+
+		if series == nil {
+			continue
+		}
+
+		if watch.category != category {
+			category = watch.category
+			fmt.Fprintln(w, category)
+		}
+
+		if watch.counter {
+			fmt.Fprintf(w, "  %s (largest increase)\n", series.Key.Name)
+
+			for _, d := range worst(series.Deltas(), n, func(d statlog.Interval) int64 { return d.Delta }) {
+				fmt.Fprintf(w, "    %s - %s  %+d\n", d.Start.Format(time.RFC3339), d.End.Format(time.RFC3339), d.Delta)
+			}
+		} else {
+			fmt.Fprintf(w, "  %s (highest value)\n", series.Key.Name)
+
+			for _, p := range worst(series.Points, n, func(p statlog.Point) int64 { return p.Value }) {
+				fmt.Fprintf(w, "    %s  %d\n", p.Time.Format(time.RFC3339), p.Value)
+			}
+		}
+	}
+}
+
+// worst returns the n elements with the largest value, largest first.
+func worst[T any](s []T, n int, value func(T) int64) []T {
+	s = slices.Clone(s)
+
+	slices.SortStableFunc(s, func(a, b T) int {
+		va, vb := value(a), value(b)
+
+		switch {
+		case va > vb:
+			return -1
+		case va < vb:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return s[:min(n, len(s))]
+}
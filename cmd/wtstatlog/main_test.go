@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/dylrich/wtgo/statlog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSummarize(t *testing.T) {
+	l, err := statlog.ReadFiles(filepath.Join("..", "..", "statlog", "testdata", "WiredTigerStat.json"))
+	if err != nil {
+		t.Fatalf("read files: %s", err)
+	}
+
+	var b strings.Builder
+
+	summarize(&b, l, 1)
+
+	want := `cache
+  cache: bytes currently in the cache (highest value)
+    2024-01-01T00:00:10Z  5000
+  cache: tracked dirty bytes in the cache (highest value)
+    2024-01-01T00:00:10Z  4000
+eviction
+  cache: pages evicted by application threads (largest increase)
+    2024-01-01T00:00:00Z - 2024-01-01T00:00:10Z  +30
+checkpoint
+  transaction: transaction checkpoint most recent time (msecs) (highest value)
+    2024-01-01T00:00:10Z  800
+  transaction: transaction checkpoint total time (msecs) (largest increase)
+    2024-01-01T00:00:00Z - 2024-01-01T00:00:10Z  +800
+`
+
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Fatalf("summary doesn't match (-want +got):\n%s", diff)
+	}
+}
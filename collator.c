@@ -0,0 +1,38 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+
+typedef struct {
+	WT_COLLATOR iface;
+	uintptr_t handle;
+} wtgo_collator;
+
+static int wtgo_collator_compare(WT_COLLATOR *collator, WT_SESSION *session, const WT_ITEM *key1, const WT_ITEM *key2, int *cmp) {
+	return wtgoCollatorCompare(((wtgo_collator *)collator)->handle, (void *)key1->data, key1->size, (void *)key2->data, key2->size, cmp);
+}
+
+static int wtgo_collator_terminate(WT_COLLATOR *collator, WT_SESSION *session) {
+	wtgoCollatorTerminate(((wtgo_collator *)collator)->handle);
+	free(collator);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_collator(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config) {
+	wtgo_collator *collator = calloc(1, sizeof(wtgo_collator));
+	if (collator == NULL) {
+		return ENOMEM;
+	}
+
+	collator->iface.compare = wtgo_collator_compare;
+	collator->iface.terminate = wtgo_collator_terminate;
+	collator->handle = handle;
+
+	int ret = connection->add_collator(connection, name, &collator->iface, config);
+	if (ret != 0) {
+		free(collator);
+	}
+
+	return ret;
+}
@@ -0,0 +1,98 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_collator(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config);
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtformat"
+	"runtime/cgo"
+	"syscall"
+	"unsafe"
+)
+
+// CollatorFunc orders two packed keys, returning a negative number when a
+// sorts before b, zero when they are equal and a positive number otherwise.
+// An error fails the WiredTiger operation that compared the keys. The
+// slices are only valid for the duration of the call. It is called from
+// WiredTiger threads and must be safe for concurrent use.
+type CollatorFunc func(a, b []byte) (int, error)
+
+// DecodedCollator adapts a comparison of decoded key fields into a
+// CollatorFunc for tables with the given key format. Keys that cannot be
+// unpacked with the format fail the comparison with EINVAL.
+func DecodedCollator(keyFormat string, compare func(a, b []any) int) (CollatorFunc, error) {
+	packers, err := wtformat.ParseFormat(keyFormat)
+	if err != nil {
+		return nil, fmt.Errorf("parse key format: %w", err)
+	}
+
+	f := func(a, b []byte) (int, error) {
+		ka, err := unpackAll(packers, a)
+		if err != nil {
+			return 0, fmt.Errorf("unpack key: %w: %w", err, ErrorCode(syscall.EINVAL))
+		}
+
+		kb, err := unpackAll(packers, b)
+		if err != nil {
+			return 0, fmt.Errorf("unpack key: %w: %w", err, ErrorCode(syscall.EINVAL))
+		}
+
+		return compare(ka, kb), nil
+	}
+
+	return f, nil
+}
+
+// AddCollator registers compare under name so that objects can be created
+// with "collator=<name>". Searches, bounds and Cursor.Compare on those
+// objects then follow its order.
+func (conn *Connection) AddCollator(name string, compare CollatorFunc) error {
+	if compare == nil {
+		return fmt.Errorf("collator %s has no compare function", name)
+	}
+
+	h := cgo.NewHandle(compare)
+
+	namecstr := C.CString(name)
+	defer C.free(unsafe.Pointer(namecstr))
+
+	if code := int(C.wiredtiger_connection_add_collator(conn.wtc, namecstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+//export wtgoCollatorCompare
+func wtgoCollatorCompare(handle C.uintptr_t, a unsafe.Pointer, aSize C.size_t, b unsafe.Pointer, bSize C.size_t, cmp *C.int) C.int {
+	compare := cgo.Handle(handle).Value().(CollatorFunc)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		c, err := compare(unsafe.Slice((*byte)(a), int(aSize)), unsafe.Slice((*byte)(b), int(bSize)))
+		if err != nil {
+			return err
+		}
+
+		*cmp = C.int(c)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoCollatorTerminate
+func wtgoCollatorTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
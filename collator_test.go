@@ -0,0 +1,228 @@
+package wtgo_test
+
+import (
+	"cmp"
+	"errors"
+	"github.com/dylrich/wtgo"
+	"strings"
+	"syscall"
+	"testing"
+
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func TestCollator(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	nocase, err := wtgo.DecodedCollator("S", func(a, b []any) int {
+		return strings.Compare(strings.ToLower(a[0].(string)), strings.ToLower(b[0].(string)))
+	})
+	if err != nil {
+		t.Fatalf("decoded collator: %s", err)
+	}
+
+	if err := env.conn.AddCollator("nocase", nocase); err != nil {
+		t.Fatalf("add nocase collator: %s", err)
+	}
+
+	reverse, err := wtgo.DecodedCollator("Q", func(a, b []any) int {
+		return cmp.Compare(b[0].(uint64), a[0].(uint64))
+	})
+	if err != nil {
+		t.Fatalf("decoded collator: %s", err)
+	}
+
+	if err := env.conn.AddCollator("reverse", reverse); err != nil {
+		t.Fatalf("add reverse collator: %s", err)
+	}
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		tablename := "table:words"
+
+		if err := env.session.Create(tablename, "key_format=S,value_format=S,collator=nocase"); err != nil {
+			t.Fatalf("create table: %s", err)
+		}
+
+		cursor, err := env.session.OpenCursor(tablename, "")
+		if err != nil {
+			t.Fatalf("open cursor: %s", err)
+		}
+
+		records := []record{
+			{k: []any{"apple"}, v: []any{"1"}},
+			{k: []any{"Banana"}, v: []any{"2"}},
+			{k: []any{"cherry"}, v: []any{"3"}},
+			{k: []any{"Date"}, v: []any{"4"}},
+			{k: []any{"éclair"}, v: []any{"5"}},
+		}
+
+		if err := seed(cursor, records); err != nil {
+			t.Fatalf("seed database: %s", err)
+		}
+
+		if diff := gocmp.Diff([]string{"apple", "Banana", "cherry", "Date", "éclair"}, scanKeys[string](t, cursor)); diff != "" {
+			t.Fatalf("order doesn't match (-want +got):\n%s", diff)
+		}
+
+		r, err := searchKey[string, string](cursor, "BANANA")
+		if err != nil {
+			t.Fatalf("search key: %s", err)
+		}
+
+		if diff := gocmp.Diff("Banana", r.Key); diff != "" {
+			t.Fatalf("found key doesn't match (-want +got):\n%s", diff)
+		}
+
+		results, err := searchNearKey[string, string](cursor, "ÉCLAIR", includeEqual, cursor.Next)
+		if err != nil {
+			t.Fatalf("search near: %s", err)
+		}
+
+		if len(results) != 1 || results[0].Key != "éclair" {
+			t.Fatalf("search near ÉCLAIR found %v, wanted éclair", results)
+		}
+
+		if err := cursor.Reset(); err != nil {
+			t.Fatalf("reset: %s", err)
+		}
+
+		if err := cursor.SetKey("b"); err != nil {
+			t.Fatalf("set lower key: %s", err)
+		}
+
+		if err := cursor.Bound("action=set,bound=lower,inclusive=true"); err != nil {
+			t.Fatalf("set lower bound: %s", err)
+		}
+
+		if err := cursor.SetKey("D"); err != nil {
+			t.Fatalf("set upper key: %s", err)
+		}
+
+		if err := cursor.Bound("action=set,bound=upper,inclusive=false"); err != nil {
+			t.Fatalf("set upper bound: %s", err)
+		}
+
+		if diff := gocmp.Diff([]string{"Banana", "cherry"}, scanKeys[string](t, cursor)); diff != "" {
+			t.Fatalf("bounded keys don't match (-want +got):\n%s", diff)
+		}
+
+		other, err := env.session.OpenCursor(tablename, "")
+		if err != nil {
+			t.Fatalf("open other cursor: %s", err)
+		}
+
+		if err := cursor.Reset(); err != nil {
+			t.Fatalf("reset: %s", err)
+		}
+
+		if err := cursor.SetKey("DATE"); err != nil {
+			t.Fatalf("set cursor key: %s", err)
+		}
+
+		if err := other.SetKey("cherry"); err != nil {
+			t.Fatalf("set other key: %s", err)
+		}
+
+		comparison, err := cursor.Compare(other)
+		if err != nil {
+			t.Fatalf("compare: %s", err)
+		}
+
+		if comparison != wtgo.CursorComparisonGreaterThan {
+			t.Fatalf("DATE compared %d to cherry, wanted greater than", comparison)
+		}
+	})
+
+	t.Run("reverse-numeric", func(t *testing.T) {
+		tablename := "table:numbers"
+
+		if err := env.session.Create(tablename, "key_format=Q,value_format=S,collator=reverse"); err != nil {
+			t.Fatalf("create table: %s", err)
+		}
+
+		cursor, err := env.session.OpenCursor(tablename, "")
+		if err != nil {
+			t.Fatalf("open cursor: %s", err)
+		}
+
+		for _, n := range []uint64{5, 300, 1, 70000, 42} {
+			if err := insert(cursor, n, "v"); err != nil {
+				t.Fatalf("insert %d: %s", n, err)
+			}
+		}
+
+		if err := cursor.Reset(); err != nil {
+			t.Fatalf("reset: %s", err)
+		}
+
+		if diff := gocmp.Diff([]uint64{70000, 300, 42, 5, 1}, scanKeys[uint64](t, cursor)); diff != "" {
+			t.Fatalf("order doesn't match (-want +got):\n%s", diff)
+		}
+
+		results, err := searchNearKey[uint64, string](cursor, uint64(100), includeGreaterThanOrEqual, cursor.Next)
+		if err != nil {
+			t.Fatalf("search near: %s", err)
+		}
+
+		want := []result[uint64, string]{
+			{Key: 42, Value: "v"},
+			{Key: 5, Value: "v"},
+			{Key: 1, Value: "v"},
+		}
+
+		if diff := gocmp.Diff(want, results); diff != "" {
+			t.Fatalf("search near results don't match (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("malformed-key", func(t *testing.T) {
+		one, err := wtgo.Pack("Q", uint64(1))
+		if err != nil {
+			t.Fatalf("pack key: %s", err)
+		}
+
+		two, err := wtgo.Pack("Q", uint64(2))
+		if err != nil {
+			t.Fatalf("pack key: %s", err)
+		}
+
+		if c, err := reverse(one, two); err != nil || c <= 0 {
+			t.Fatalf("compare returned %d, %v, expected a positive result", c, err)
+		}
+
+		if _, err := reverse(one, []byte{0x00}); !errors.Is(err, wtgo.ErrorCode(syscall.EINVAL)) {
+			t.Fatalf("compare returned err '%v', expected EINVAL", err)
+		}
+
+		if _, err := reverse(one[:0], two); !errors.Is(err, wtgo.ErrorCode(syscall.EINVAL)) {
+			t.Fatalf("compare returned err '%v', expected EINVAL", err)
+		}
+	})
+}
+
+func scanKeys[K any](t *testing.T, cursor *wtgo.Cursor) []K {
+	t.Helper()
+
+	keys := make([]K, 0, 8)
+
+	for cursor.Next() {
+		var k K
+
+		if err := cursor.GetKey(&k); err != nil {
+			t.Fatalf("get key: %s", err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	return keys
+}
@@ -0,0 +1,48 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+
+typedef struct {
+	WT_COMPRESSOR iface;
+	uintptr_t handle;
+} wtgo_compressor;
+
+static int wtgo_compressor_compress(WT_COMPRESSOR *compressor, WT_SESSION *session, uint8_t *src, size_t src_len, uint8_t *dst, size_t dst_len, size_t *result_lenp, int *compression_failed) {
+	return wtgoCompressorCompress(((wtgo_compressor *)compressor)->handle, src, src_len, dst, dst_len, result_lenp, compression_failed);
+}
+
+static int wtgo_compressor_decompress(WT_COMPRESSOR *compressor, WT_SESSION *session, uint8_t *src, size_t src_len, uint8_t *dst, size_t dst_len, size_t *result_lenp) {
+	return wtgoCompressorDecompress(((wtgo_compressor *)compressor)->handle, src, src_len, dst, dst_len, result_lenp);
+}
+
+static int wtgo_compressor_pre_size(WT_COMPRESSOR *compressor, WT_SESSION *session, uint8_t *src, size_t src_len, size_t *result_lenp) {
+	return wtgoCompressorPreSize(((wtgo_compressor *)compressor)->handle, src, src_len, result_lenp);
+}
+
+static int wtgo_compressor_terminate(WT_COMPRESSOR *compressor, WT_SESSION *session) {
+	wtgoCompressorTerminate(((wtgo_compressor *)compressor)->handle);
+	free(compressor);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_compressor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config) {
+	wtgo_compressor *compressor = calloc(1, sizeof(wtgo_compressor));
+	if (compressor == NULL) {
+		return ENOMEM;
+	}
+
+	compressor->iface.compress = wtgo_compressor_compress;
+	compressor->iface.decompress = wtgo_compressor_decompress;
+	compressor->iface.pre_size = wtgo_compressor_pre_size;
+	compressor->iface.terminate = wtgo_compressor_terminate;
+	compressor->handle = handle;
+
+	int ret = connection->add_compressor(connection, name, &compressor->iface, config);
+	if (ret != 0) {
+		free(compressor);
+	}
+
+	return ret;
+}
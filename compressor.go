@@ -0,0 +1,174 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_compressor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config);
+*/
+import (
+	"C"
+)
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Compressor is a block compressor implemented in Go. Its methods are called
+// from WiredTiger threads and must be safe for concurrent use. The src and
+// dst slices point into WiredTiger's buffers and must not be retained.
+type Compressor interface {
+	// Compress writes the compressed form of src into dst and returns its
+	// length. It returns ok=false when the result would not fit in dst, in
+	// which case WiredTiger stores the block uncompressed.
+	Compress(dst, src []byte) (n int, ok bool, err error)
+
+	// Decompress writes the decompressed form of src into dst and returns
+	// its length.
+	Decompress(dst, src []byte) (int, error)
+
+	// PreSize returns the size of the buffer Compress needs for src.
+	PreSize(src []byte) int
+}
+
+// AddCompressor registers c under name so that objects can be created with
+// "block_compressor=<name>". It must be registered again after each Open,
+// before any such object is used.
+func (conn *Connection) AddCompressor(name string, c Compressor) error {
+	if c == nil {
+		return fmt.Errorf("compressor %s is nil", name)
+	}
+
+	h := cgo.NewHandle(c)
+
+	namecstr := C.CString(name)
+	defer C.free(unsafe.Pointer(namecstr))
+
+	if code := int(C.wiredtiger_connection_add_compressor(conn.wtc, namecstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+//export wtgoCompressorCompress
+func wtgoCompressorCompress(handle C.uintptr_t, src *C.uint8_t, srcLen C.size_t, dst *C.uint8_t, dstLen C.size_t, resultLen *C.size_t, failed *C.int) C.int {
+	c := cgo.Handle(handle).Value().(Compressor)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		n, ok, err := c.Compress(cBytes(dst, dstLen), cBytes(src, srcLen))
+		if err != nil {
+			return err
+		}
+
+		*failed = 0
+		if !ok {
+			*failed = 1
+		}
+
+		*resultLen = C.size_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoCompressorDecompress
+func wtgoCompressorDecompress(handle C.uintptr_t, src *C.uint8_t, srcLen C.size_t, dst *C.uint8_t, dstLen C.size_t, resultLen *C.size_t) C.int {
+	c := cgo.Handle(handle).Value().(Compressor)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		n, err := c.Decompress(cBytes(dst, dstLen), cBytes(src, srcLen))
+		if err != nil {
+			return err
+		}
+
+		*resultLen = C.size_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoCompressorPreSize
+func wtgoCompressorPreSize(handle C.uintptr_t, src *C.uint8_t, srcLen C.size_t, resultLen *C.size_t) C.int {
+	c := cgo.Handle(handle).Value().(Compressor)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		*resultLen = C.size_t(c.PreSize(cBytes(src, srcLen)))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoCompressorTerminate
+func wtgoCompressorTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+func cBytes(p *C.uint8_t, n C.size_t) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(p)), int(n))
+}
+
+// FlateCompressor compresses blocks with compress/flate at Level.
+type FlateCompressor struct {
+	Level int
+}
+
+func (f FlateCompressor) Compress(dst, src []byte) (int, bool, error) {
+	var buf bytes.Buffer
+
+	w, err := flate.NewWriter(&buf, f.Level)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if _, err := w.Write(src); err != nil {
+		return 0, false, err
+	}
+
+	if err := w.Close(); err != nil {
+		return 0, false, err
+	}
+
+	if buf.Len() > len(dst) {
+		return 0, false, nil
+	}
+
+	return copy(dst, buf.Bytes()), true, nil
+}
+
+func (f FlateCompressor) Decompress(dst, src []byte) (int, error) {
+	r := flate.NewReader(bytes.NewReader(src))
+	defer r.Close()
+
+	n, err := io.ReadFull(r, dst)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// PreSize allows for flate's worst case of storing incompressible input in
+// raw blocks, each of which adds 5 bytes of framing per 64KiB.
+func (f FlateCompressor) PreSize(src []byte) int {
+	return len(src) + (len(src)/65535+1)*5 + 64
+}
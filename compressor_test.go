@@ -0,0 +1,131 @@
+package wtgo_test
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type countingCompressor struct {
+	wtgo.Compressor
+	compressed   atomic.Int64
+	decompressed atomic.Int64
+}
+
+func (c *countingCompressor) Compress(dst, src []byte) (int, bool, error) {
+	c.compressed.Add(1)
+	return c.Compressor.Compress(dst, src)
+}
+
+func (c *countingCompressor) Decompress(dst, src []byte) (int, error) {
+	c.decompressed.Add(1)
+	return c.Compressor.Decompress(dst, src)
+}
+
+func TestCompressor(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	compressor := &countingCompressor{Compressor: wtgo.FlateCompressor{Level: 6}}
+
+	if err := env.conn.AddCompressor("goflate", compressor); err != nil {
+		t.Fatalf("add compressor: %s", err)
+	}
+
+	tablename := "table:compressed"
+
+	if err := env.session.Create(tablename, "key_format=Q,value_format=S,block_compressor=goflate"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	cursor, err := env.session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	value := func(i uint64) string {
+		return fmt.Sprintf("%d:%s", i, strings.Repeat("compressible ", 20))
+	}
+
+	const rows = 2000
+
+	for i := uint64(0); i < rows; i++ {
+		if err := insert(cursor, i, value(i)); err != nil {
+			t.Fatalf("insert %d: %s", i, err)
+		}
+	}
+
+	if err := env.session.Checkpoint(""); err != nil {
+		t.Fatalf("checkpoint: %s", err)
+	}
+
+	if err := env.conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	if compressor.compressed.Load() == 0 {
+		t.Fatalf("compressor was never called")
+	}
+
+	conn, err := openTestConnection(env.dir, "", wtgo.OpenOptions{})
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	reopened := &countingCompressor{Compressor: wtgo.FlateCompressor{Level: 6}}
+
+	if err := conn.AddCompressor("goflate", reopened); err != nil {
+		t.Fatalf("add compressor after reopen: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	cursor, err = session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor after reopen: %s", err)
+	}
+
+	var n uint64
+
+	for ; cursor.Next(); n++ {
+		var k uint64
+		var v string
+
+		if err := cursor.GetKey(&k); err != nil {
+			t.Fatalf("get key: %s", err)
+		}
+
+		if err := cursor.GetValue(&v); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		if diff := cmp.Diff([]any{n, value(n)}, []any{k, v}); diff != "" {
+			t.Fatalf("row doesn't match (-want +got):\n%s", diff)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	if n != rows {
+		t.Fatalf("read %d rows after reopen, wanted %d", n, rows)
+	}
+
+	if reopened.decompressed.Load() == 0 {
+		t.Fatalf("rows were not read back through the compressor")
+	}
+}
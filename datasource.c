@@ -0,0 +1,176 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+#include <string.h>
+
+typedef struct {
+	WT_DATA_SOURCE iface;
+	WT_EXTENSION_API *api;
+	uintptr_t handle;
+} wtgo_data_source;
+
+typedef struct {
+	void *data;
+	size_t cap;
+} wtgo_buffer;
+
+typedef struct {
+	WT_CURSOR iface;
+	uintptr_t handle;
+	char *key_format;
+	char *value_format;
+	wtgo_buffer key;
+	wtgo_buffer value;
+} wtgo_data_source_cursor;
+
+static int wtgo_buffer_set(wtgo_buffer *buf, WT_ITEM *item, const void *data, size_t size) {
+	if (size > buf->cap) {
+		void *p = realloc(buf->data, size);
+		if (p == NULL) {
+			return ENOMEM;
+		}
+
+		buf->data = p;
+		buf->cap = size;
+	}
+
+	if (size > 0) {
+		memmove(buf->data, data, size);
+	}
+
+	item->data = buf->data;
+	item->size = size;
+
+	return 0;
+}
+
+// wtgo_data_source_cursor_set copies a key and value returned by Go into
+// memory owned by the cursor, where WiredTiger reads them after the call.
+int wtgo_data_source_cursor_set(WT_CURSOR *cursor, const void *key, size_t key_len, const void *value, size_t value_len) {
+	wtgo_data_source_cursor *c = (wtgo_data_source_cursor *)cursor;
+
+	int ret = wtgo_buffer_set(&c->key, &cursor->key, key, key_len);
+	if (ret != 0) {
+		return ret;
+	}
+
+	return wtgo_buffer_set(&c->value, &cursor->value, value, value_len);
+}
+
+static int wtgo_data_source_cursor_next(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorNext(((wtgo_data_source_cursor *)cursor)->handle, cursor);
+}
+
+static int wtgo_data_source_cursor_prev(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorPrev(((wtgo_data_source_cursor *)cursor)->handle, cursor);
+}
+
+static int wtgo_data_source_cursor_reset(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorReset(((wtgo_data_source_cursor *)cursor)->handle);
+}
+
+static int wtgo_data_source_cursor_search(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorSearch(((wtgo_data_source_cursor *)cursor)->handle, cursor, (void *)cursor->key.data, cursor->key.size);
+}
+
+static int wtgo_data_source_cursor_search_near(WT_CURSOR *cursor, int *exactp) {
+	return wtgoDataSourceCursorSearchNear(((wtgo_data_source_cursor *)cursor)->handle, cursor, (void *)cursor->key.data, cursor->key.size, exactp);
+}
+
+static int wtgo_data_source_cursor_insert(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorInsert(((wtgo_data_source_cursor *)cursor)->handle, (void *)cursor->key.data, cursor->key.size, (void *)cursor->value.data, cursor->value.size);
+}
+
+static int wtgo_data_source_cursor_update(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorUpdate(((wtgo_data_source_cursor *)cursor)->handle, (void *)cursor->key.data, cursor->key.size, (void *)cursor->value.data, cursor->value.size);
+}
+
+static int wtgo_data_source_cursor_remove(WT_CURSOR *cursor) {
+	return wtgoDataSourceCursorRemove(((wtgo_data_source_cursor *)cursor)->handle, (void *)cursor->key.data, cursor->key.size);
+}
+
+static int wtgo_data_source_cursor_close(WT_CURSOR *cursor) {
+	wtgo_data_source_cursor *c = (wtgo_data_source_cursor *)cursor;
+
+	int ret = wtgoDataSourceCursorClose(c->handle);
+
+	free(c->key_format);
+	free(c->value_format);
+	free(c->key.data);
+	free(c->value.data);
+	free(c);
+
+	return ret;
+}
+
+static int wtgo_data_source_create(WT_DATA_SOURCE *dsrc, WT_SESSION *session, const char *uri, WT_CONFIG_ARG *config) {
+	wtgo_data_source *d = (wtgo_data_source *)dsrc;
+	return wtgoDataSourceCreate(d->handle, d->api, session, (char *)uri, config);
+}
+
+static int wtgo_data_source_drop(WT_DATA_SOURCE *dsrc, WT_SESSION *session, const char *uri, WT_CONFIG_ARG *config) {
+	wtgo_data_source *d = (wtgo_data_source *)dsrc;
+	return wtgoDataSourceDrop(d->handle, d->api, session, (char *)uri, config);
+}
+
+static int wtgo_data_source_open_cursor(WT_DATA_SOURCE *dsrc, WT_SESSION *session, const char *uri, WT_CONFIG_ARG *config, WT_CURSOR **new_cursor) {
+	wtgo_data_source *d = (wtgo_data_source *)dsrc;
+
+	wtgo_data_source_cursor *c = calloc(1, sizeof(wtgo_data_source_cursor));
+	if (c == NULL) {
+		return ENOMEM;
+	}
+
+	int ret = wtgoDataSourceOpenCursor(d->handle, d->api, session, (char *)uri, config, &c->handle, &c->key_format, &c->value_format);
+	if (ret != 0) {
+		free(c);
+		return ret;
+	}
+
+	c->iface.session = session;
+	c->iface.uri = uri;
+	c->iface.key_format = c->key_format;
+	c->iface.value_format = c->value_format;
+	c->iface.next = wtgo_data_source_cursor_next;
+	c->iface.prev = wtgo_data_source_cursor_prev;
+	c->iface.reset = wtgo_data_source_cursor_reset;
+	c->iface.search = wtgo_data_source_cursor_search;
+	c->iface.search_near = wtgo_data_source_cursor_search_near;
+	c->iface.insert = wtgo_data_source_cursor_insert;
+	c->iface.update = wtgo_data_source_cursor_update;
+	c->iface.remove = wtgo_data_source_cursor_remove;
+	c->iface.close = wtgo_data_source_cursor_close;
+
+	*new_cursor = &c->iface;
+
+	return 0;
+}
+
+static int wtgo_data_source_terminate(WT_DATA_SOURCE *dsrc, WT_SESSION *session) {
+	wtgoDataSourceTerminate(((wtgo_data_source *)dsrc)->handle);
+	free(dsrc);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_data_source(WT_CONNECTION *connection, const char *prefix, uintptr_t handle, const char *config) {
+	wtgo_data_source *dsrc = calloc(1, sizeof(wtgo_data_source));
+	if (dsrc == NULL) {
+		return ENOMEM;
+	}
+
+	dsrc->iface.create = wtgo_data_source_create;
+	dsrc->iface.drop = wtgo_data_source_drop;
+	dsrc->iface.open_cursor = wtgo_data_source_open_cursor;
+	dsrc->iface.terminate = wtgo_data_source_terminate;
+	dsrc->api = connection->get_extension_api(connection);
+	dsrc->handle = handle;
+
+	int ret = connection->add_data_source(connection, prefix, &dsrc->iface, config);
+	if (ret != 0) {
+		free(dsrc);
+	}
+
+	return ret;
+}
@@ -0,0 +1,358 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_data_source(WT_CONNECTION *connection, const char *prefix, uintptr_t handle, const char *config);
+int wtgo_data_source_cursor_set(WT_CURSOR *cursor, const void *key, size_t key_len, const void *value, size_t value_len);
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtconfig"
+	"runtime/cgo"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// DataSource serves every object whose URI starts with the prefix it was
+// registered under. Its methods are called from WiredTiger threads and must
+// be safe for concurrent use. The key_format and value_format given to
+// Create are recorded in the metadata by wtgo, so Session.OpenCursor returns
+// an ordinary *Cursor for the object.
+type DataSource interface {
+	Create(uri, config string) error
+	Drop(uri, config string) error
+	OpenCursor(uri, config string) (DataSourceCursor, error)
+}
+
+// DataSourceCursor implements the cursor methods of a DataSource object.
+// Keys and values are in the packed form of the object's formats; see Pack
+// and Unpack. Slices passed in must not be retained, and slices returned
+// are copied before the method returns to WiredTiger. Positioning methods
+// return ErrNotFound when there is no matching record. Record number keys
+// are not supported.
+type DataSourceCursor interface {
+	Next() (key, value []byte, err error)
+	Prev() (key, value []byte, err error)
+	Search(key []byte) (value []byte, err error)
+	SearchNear(key []byte) (near, value []byte, cmp CursorComparison, err error)
+	Insert(key, value []byte) error
+	Update(key, value []byte) error
+	Remove(key []byte) error
+	Reset() error
+	Close() error
+}
+
+// AddDataSource registers d for URIs starting with prefix, which must end
+// with a colon, e.g. "mysrc:".
+func (conn *Connection) AddDataSource(prefix string, d DataSource) error {
+	if d == nil {
+		return fmt.Errorf("data source %s is nil", prefix)
+	}
+
+	if !strings.HasSuffix(prefix, ":") {
+		return fmt.Errorf("data source prefix '%s' does not end with a colon", prefix)
+	}
+
+	h := cgo.NewHandle(d)
+
+	prefixcstr := C.CString(prefix)
+	defer C.free(unsafe.Pointer(prefixcstr))
+
+	if code := int(C.wiredtiger_connection_add_data_source(conn.wtc, prefixcstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+//export wtgoDataSourceCreate
+func wtgoDataSourceCreate(handle C.uintptr_t, api *C.WT_EXTENSION_API, session *C.WT_SESSION, uricstr *C.char, config *C.WT_CONFIG_ARG) C.int {
+	d := cgo.Handle(handle).Value().(DataSource)
+	e := extensionAPI{api: api, session: session}
+	uri := C.GoString(uricstr)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		_, exists, err := e.metadata(uri)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			exclusive, err := e.configBool(config, "exclusive")
+			if err != nil {
+				return fmt.Errorf("read exclusive: %w", err)
+			}
+
+			if exclusive {
+				return fmt.Errorf("%s already exists: %w", uri, ErrorCode(syscall.EEXIST))
+			}
+
+			return nil
+		}
+
+		c, err := e.configString(config)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+
+		keyFormat, _, err := e.configGet(config, "key_format")
+		if err != nil {
+			return fmt.Errorf("read key_format: %w", err)
+		}
+
+		valueFormat, _, err := e.configGet(config, "value_format")
+		if err != nil {
+			return fmt.Errorf("read value_format: %w", err)
+		}
+
+		if strings.Contains(keyFormat, "r") {
+			return fmt.Errorf("create %s: record number keys are not supported: %w", uri, ErrorCode(syscall.ENOTSUP))
+		}
+
+		if err := d.Create(uri, c); err != nil {
+			return err
+		}
+
+		meta := "key_format=" + keyFormat + ",value_format=" + valueFormat
+		if c != "" {
+			meta = c + "," + meta
+		}
+
+		return e.insertMetadata(uri, meta)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceDrop
+func wtgoDataSourceDrop(handle C.uintptr_t, api *C.WT_EXTENSION_API, session *C.WT_SESSION, uricstr *C.char, config *C.WT_CONFIG_ARG) C.int {
+	d := cgo.Handle(handle).Value().(DataSource)
+	e := extensionAPI{api: api, session: session}
+	uri := C.GoString(uricstr)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		c, err := e.configString(config)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+
+		if err := d.Drop(uri, c); err != nil {
+			return err
+		}
+
+		return e.removeMetadata(uri)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceOpenCursor
+func wtgoDataSourceOpenCursor(handle C.uintptr_t, api *C.WT_EXTENSION_API, session *C.WT_SESSION, uricstr *C.char, config *C.WT_CONFIG_ARG, cursorHandle *C.uintptr_t, keyFormat, valueFormat **C.char) C.int {
+	d := cgo.Handle(handle).Value().(DataSource)
+	e := extensionAPI{api: api, session: session}
+	uri := C.GoString(uricstr)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		meta, ok, err := e.metadata(uri)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("%s does not exist: %w", uri, ErrorCode(syscall.ENOENT))
+		}
+
+		kf, _, err := wtconfig.Get(meta, "key_format")
+		if err != nil {
+			return fmt.Errorf("parse metadata of %s: %w", uri, err)
+		}
+
+		vf, _, err := wtconfig.Get(meta, "value_format")
+		if err != nil {
+			return fmt.Errorf("parse metadata of %s: %w", uri, err)
+		}
+
+		c, err := e.configString(config)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+
+		cursor, err := d.OpenCursor(uri, c)
+		if err != nil {
+			return err
+		}
+
+		*cursorHandle = C.uintptr_t(cgo.NewHandle(cursor))
+		*keyFormat = C.CString(kf)
+		*valueFormat = C.CString(vf)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceTerminate
+func wtgoDataSourceTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+func dataSourceCursor(handle C.uintptr_t) DataSourceCursor {
+	return cgo.Handle(handle).Value().(DataSourceCursor)
+}
+
+func setDataSourceCursor(cursor *C.WT_CURSOR, key, value []byte) error {
+	if code := int(C.wtgo_data_source_cursor_set(cursor, bytesPointer(key), C.size_t(len(key)), bytesPointer(value), C.size_t(len(value)))); code != 0 {
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+func bytesPointer(b []byte) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return unsafe.Pointer(&b[0])
+}
+
+//export wtgoDataSourceCursorNext
+func wtgoDataSourceCursorNext(handle C.uintptr_t, cursor *C.WT_CURSOR) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		key, value, err := dataSourceCursor(handle).Next()
+		if err != nil {
+			return err
+		}
+
+		return setDataSourceCursor(cursor, key, value)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorPrev
+func wtgoDataSourceCursorPrev(handle C.uintptr_t, cursor *C.WT_CURSOR) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		key, value, err := dataSourceCursor(handle).Prev()
+		if err != nil {
+			return err
+		}
+
+		return setDataSourceCursor(cursor, key, value)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorReset
+func wtgoDataSourceCursorReset(handle C.uintptr_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return dataSourceCursor(handle).Reset()
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorSearch
+func wtgoDataSourceCursorSearch(handle C.uintptr_t, cursor *C.WT_CURSOR, key *C.uint8_t, keyLen C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		k := cBytes(key, keyLen)
+
+		value, err := dataSourceCursor(handle).Search(k)
+		if err != nil {
+			return err
+		}
+
+		return setDataSourceCursor(cursor, k, value)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorSearchNear
+func wtgoDataSourceCursorSearchNear(handle C.uintptr_t, cursor *C.WT_CURSOR, key *C.uint8_t, keyLen C.size_t, exact *C.int) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		near, value, cmp, err := dataSourceCursor(handle).SearchNear(cBytes(key, keyLen))
+		if err != nil {
+			return err
+		}
+
+		*exact = C.int(cmp)
+
+		return setDataSourceCursor(cursor, near, value)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorInsert
+func wtgoDataSourceCursorInsert(handle C.uintptr_t, key *C.uint8_t, keyLen C.size_t, value *C.uint8_t, valueLen C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return dataSourceCursor(handle).Insert(cBytes(key, keyLen), cBytes(value, valueLen))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorUpdate
+func wtgoDataSourceCursorUpdate(handle C.uintptr_t, key *C.uint8_t, keyLen C.size_t, value *C.uint8_t, valueLen C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return dataSourceCursor(handle).Update(cBytes(key, keyLen), cBytes(value, valueLen))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorRemove
+func wtgoDataSourceCursorRemove(handle C.uintptr_t, key *C.uint8_t, keyLen C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return dataSourceCursor(handle).Remove(cBytes(key, keyLen))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoDataSourceCursorClose
+func wtgoDataSourceCursorClose(handle C.uintptr_t) C.int {
+	h := cgo.Handle(handle)
+	defer h.Delete()
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return h.Value().(DataSourceCursor).Close()
+	}()
+
+	return C.int(callbackCode(err))
+}
@@ -0,0 +1,375 @@
+package wtgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/dylrich/wtgo"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type memEntry struct {
+	key   []byte
+	value []byte
+}
+
+type memTable struct {
+	mu      sync.Mutex
+	entries []memEntry
+}
+
+func (t *memTable) find(key []byte) (int, bool) {
+	return slices.BinarySearchFunc(t.entries, key, func(e memEntry, k []byte) int {
+		return bytes.Compare(e.key, k)
+	})
+}
+
+// memDataSource keeps each object as a sorted slice of packed records.
+type memDataSource struct {
+	mu      sync.Mutex
+	tables  map[string]*memTable
+	configs map[string]string
+}
+
+func newMemDataSource() *memDataSource {
+	return &memDataSource{tables: make(map[string]*memTable), configs: make(map[string]string)}
+}
+
+func (d *memDataSource) Create(uri, config string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.tables[uri] = &memTable{}
+	d.configs[uri] = config
+
+	return nil
+}
+
+func (d *memDataSource) Drop(uri, config string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.tables, uri)
+
+	return nil
+}
+
+func (d *memDataSource) OpenCursor(uri, config string) (wtgo.DataSourceCursor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t, ok := d.tables[uri]
+	if !ok {
+		return nil, wtgo.ErrNotFound
+	}
+
+	return &memCursor{table: t, pos: -1}, nil
+}
+
+type memCursor struct {
+	table *memTable
+	pos   int
+}
+
+func (c *memCursor) at(pos int) ([]byte, []byte, error) {
+	if pos < 0 || pos >= len(c.table.entries) {
+		c.pos = -1
+		return nil, nil, wtgo.ErrNotFound
+	}
+
+	c.pos = pos
+
+	return c.table.entries[pos].key, c.table.entries[pos].value, nil
+}
+
+func (c *memCursor) Next() ([]byte, []byte, error) {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	return c.at(c.pos + 1)
+}
+
+func (c *memCursor) Prev() ([]byte, []byte, error) {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	if c.pos < 0 {
+		return c.at(len(c.table.entries) - 1)
+	}
+
+	return c.at(c.pos - 1)
+}
+
+func (c *memCursor) Search(key []byte) ([]byte, error) {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	i, ok := c.table.find(key)
+	if !ok {
+		return nil, wtgo.ErrNotFound
+	}
+
+	_, value, err := c.at(i)
+
+	return value, err
+}
+
+func (c *memCursor) SearchNear(key []byte) ([]byte, []byte, wtgo.CursorComparison, error) {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	i, ok := c.table.find(key)
+	if ok {
+		k, v, err := c.at(i)
+		return k, v, wtgo.CursorComparisonEqual, err
+	}
+
+	if i < len(c.table.entries) {
+		k, v, err := c.at(i)
+		return k, v, wtgo.CursorComparisonGreaterThan, err
+	}
+
+	k, v, err := c.at(i - 1)
+
+	return k, v, wtgo.CursorComparisonLessThan, err
+}
+
+func (c *memCursor) Insert(key, value []byte) error {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	e := memEntry{key: bytes.Clone(key), value: bytes.Clone(value)}
+
+	i, ok := c.table.find(key)
+	if ok {
+		c.table.entries[i] = e
+	} else {
+		c.table.entries = slices.Insert(c.table.entries, i, e)
+	}
+
+	c.pos = -1
+
+	return nil
+}
+
+func (c *memCursor) Update(key, value []byte) error {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	i, ok := c.table.find(key)
+	if !ok {
+		return wtgo.ErrNotFound
+	}
+
+	c.table.entries[i].value = bytes.Clone(value)
+
+	return nil
+}
+
+func (c *memCursor) Remove(key []byte) error {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	i, ok := c.table.find(key)
+	if !ok {
+		return wtgo.ErrNotFound
+	}
+
+	c.table.entries = slices.Delete(c.table.entries, i, i+1)
+	c.pos = -1
+
+	return nil
+}
+
+func (c *memCursor) Reset() error {
+	c.pos = -1
+	return nil
+}
+
+func (c *memCursor) Close() error {
+	return nil
+}
+
+func TestDataSource(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	source := newMemDataSource()
+
+	if err := env.conn.AddDataSource("mem:", source); err != nil {
+		t.Fatalf("add data source: %s", err)
+	}
+
+	uri := "mem:fruit"
+
+	if err := env.session.Create(uri, "key_format=S,value_format=Q"); err != nil {
+		t.Fatalf("create: %s", err)
+	}
+
+	cursor, err := env.session.OpenCursor(uri, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	data := []record{
+		{k: []any{"cherry"}, v: []any{uint64(3)}},
+		{k: []any{"apple"}, v: []any{uint64(1)}},
+		{k: []any{"banana"}, v: []any{uint64(2)}},
+		{k: []any{"elderberry"}, v: []any{uint64(5)}},
+	}
+
+	if err := seed(cursor, data); err != nil {
+		t.Fatalf("seed: %s", err)
+	}
+
+	// Records reach the data source in packed form.
+	key, err := wtgo.Pack("S", "banana")
+	if err != nil {
+		t.Fatalf("pack key: %s", err)
+	}
+
+	table := source.tables[uri]
+
+	i, ok := table.find(key)
+	if !ok {
+		t.Fatalf("banana was not stored in the data source")
+	}
+
+	var stored uint64
+
+	if err := wtgo.Unpack("Q", table.entries[i].value, &stored); err != nil {
+		t.Fatalf("unpack stored value: %s", err)
+	}
+
+	if stored != 2 {
+		t.Fatalf("stored value is %d, wanted 2", stored)
+	}
+
+	want := []result[string, uint64]{
+		{Key: "apple", Value: 1},
+		{Key: "banana", Value: 2},
+		{Key: "cherry", Value: 3},
+		{Key: "elderberry", Value: 5},
+	}
+
+	var got []result[string, uint64]
+
+	for cursor.Next() {
+		r, err := getResult[string, uint64](cursor)
+		if err != nil {
+			t.Fatalf("get result: %s", err)
+		}
+
+		got = append(got, *r)
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("scan doesn't match (-want +got):\n%s", diff)
+	}
+
+	r, err := searchKey[string, uint64](cursor, "cherry")
+	if err != nil {
+		t.Fatalf("search: %s", err)
+	}
+
+	if diff := cmp.Diff(want[2], *r); diff != "" {
+		t.Fatalf("search result doesn't match (-want +got):\n%s", diff)
+	}
+
+	near, err := searchNearKey[string, uint64](cursor, "date", includeGreaterThanOrEqual, cursor.Next)
+	if err != nil {
+		t.Fatalf("search near: %s", err)
+	}
+
+	if diff := cmp.Diff(want[3:], near); diff != "" {
+		t.Fatalf("search near results don't match (-want +got):\n%s", diff)
+	}
+
+	if err := cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	if err := cursor.SetKey("banana"); err != nil {
+		t.Fatalf("set key: %s", err)
+	}
+
+	if err := cursor.Remove(); err != nil {
+		t.Fatalf("remove: %s", err)
+	}
+
+	if _, err := searchKey[string, uint64](cursor, "banana"); !errors.Is(err, wtgo.ErrNotFound) {
+		t.Fatalf("search for removed key returned err '%v', expected not found", err)
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("close cursor: %s", err)
+	}
+
+	if err := env.session.Drop(uri, ""); err != nil {
+		t.Fatalf("drop: %s", err)
+	}
+
+	if _, ok := source.tables[uri]; ok {
+		t.Fatalf("data source still has %s after drop", uri)
+	}
+
+	if _, err := env.session.OpenCursor(uri, ""); err == nil {
+		t.Fatalf("opened cursor on dropped object")
+	}
+}
+
+func TestDataSourceCreate(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	source := newMemDataSource()
+
+	if err := env.conn.AddDataSource("mem:", source); err != nil {
+		t.Fatalf("add data source: %s", err)
+	}
+
+	uri := "mem:quoted"
+	metadata := `app_metadata="say \"hi\", (bye)"`
+
+	if err := env.session.Create(uri, "key_format=S,value_format=S,"+metadata); err != nil {
+		t.Fatalf("create: %s", err)
+	}
+
+	if config := source.configs[uri]; !strings.Contains(config, metadata) {
+		t.Fatalf("create config '%s' doesn't contain '%s'", config, metadata)
+	}
+
+	cursor, err := env.session.OpenCursor(uri, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("close cursor: %s", err)
+	}
+
+	if err := env.session.Create(uri, "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create existing object: %s", err)
+	}
+
+	if err := env.session.Create(uri, "key_format=S,value_format=S,exclusive=true"); !errors.Is(err, wtgo.ErrorCode(syscall.EEXIST)) {
+		t.Fatalf("exclusive create of existing object returned err '%v', expected EEXIST", err)
+	}
+}
@@ -0,0 +1,17 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+
+// wtgo_early_load is named as the entry point of the "local" extension that
+// OpenWithOptions adds to the wiredtiger_open config. WiredTiger finds it
+// in the running binary, so it has to be exported dynamically.
+int wtgo_early_load(WT_CONNECTION *connection, WT_CONFIG_ARG *config) {
+	WT_EXTENSION_API *api = connection->get_extension_api(connection);
+	WT_CONFIG_ITEM item;
+
+	int ret = api->config_get(api, NULL, config, "wtgo_handle", &item);
+	if (ret != 0) {
+		return ret;
+	}
+
+	return wtgoEarlyLoad(connection, (uintptr_t)item.val);
+}
@@ -0,0 +1,63 @@
+package wtgo
+
+/*
+#cgo LDFLAGS: -rdynamic
+#include "wiredtiger.h"
+#include <stdlib.h>
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"runtime/cgo"
+)
+
+// earlyLoad runs fns from the "local" extension that its config adds to
+// the wiredtiger_open config, and keeps the first error they return.
+type earlyLoad struct {
+	fns    []func(conn *Connection) error
+	err    error
+	handle cgo.Handle
+}
+
+func newEarlyLoad(fns []func(conn *Connection) error) *earlyLoad {
+	e := &earlyLoad{fns: fns}
+	e.handle = cgo.NewHandle(e)
+
+	return e
+}
+
+func (e *earlyLoad) config(config string) (string, error) {
+	ext := fmt.Sprintf("local=(entry=wtgo_early_load,early_load=true,config=(wtgo_handle=%d))", uintptr(e.handle))
+
+	return appendExtension(config, ext)
+}
+
+func (e *earlyLoad) close() {
+	e.handle.Delete()
+}
+
+//export wtgoEarlyLoad
+func wtgoEarlyLoad(wtc *C.WT_CONNECTION, handle C.uintptr_t) C.int {
+	state := cgo.Handle(handle).Value().(*earlyLoad)
+
+	conn := &Connection{wtc: wtc}
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		for _, fn := range state.fns {
+			if err := fn(conn); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}()
+
+	state.err = err
+
+	return C.int(callbackCode(err))
+}
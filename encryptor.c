@@ -0,0 +1,81 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+
+typedef struct {
+	WT_ENCRYPTOR iface;
+	WT_EXTENSION_API *api;
+	uintptr_t handle;
+} wtgo_encryptor;
+
+static int wtgo_encryptor_encrypt(WT_ENCRYPTOR *encryptor, WT_SESSION *session, uint8_t *src, size_t src_len, uint8_t *dst, size_t dst_len, size_t *result_lenp) {
+	return wtgoEncryptorEncrypt(((wtgo_encryptor *)encryptor)->handle, src, src_len, dst, dst_len, result_lenp);
+}
+
+static int wtgo_encryptor_decrypt(WT_ENCRYPTOR *encryptor, WT_SESSION *session, uint8_t *src, size_t src_len, uint8_t *dst, size_t dst_len, size_t *result_lenp) {
+	return wtgoEncryptorDecrypt(((wtgo_encryptor *)encryptor)->handle, src, src_len, dst, dst_len, result_lenp);
+}
+
+static int wtgo_encryptor_sizing(WT_ENCRYPTOR *encryptor, WT_SESSION *session, size_t *expansion_constantp) {
+	return wtgoEncryptorSizing(((wtgo_encryptor *)encryptor)->handle, expansion_constantp);
+}
+
+static int wtgo_encryptor_customize(WT_ENCRYPTOR *encryptor, WT_SESSION *session, WT_CONFIG_ARG *encrypt_config, WT_ENCRYPTOR **customp) {
+	wtgo_encryptor *base = (wtgo_encryptor *)encryptor;
+	WT_CONFIG_ITEM keyid;
+
+	int ret = base->api->config_get(base->api, session, encrypt_config, "keyid", &keyid);
+	if (ret == WT_NOTFOUND) {
+		keyid.str = "";
+		keyid.len = 0;
+	} else if (ret != 0) {
+		return ret;
+	}
+
+	wtgo_encryptor *custom = calloc(1, sizeof(wtgo_encryptor));
+	if (custom == NULL) {
+		return ENOMEM;
+	}
+
+	*custom = *base;
+
+	ret = wtgoEncryptorCustomize(base->handle, (char *)keyid.str, keyid.len, &custom->handle);
+	if (ret != 0) {
+		free(custom);
+		return ret;
+	}
+
+	*customp = &custom->iface;
+
+	return 0;
+}
+
+static int wtgo_encryptor_terminate(WT_ENCRYPTOR *encryptor, WT_SESSION *session) {
+	wtgoEncryptorTerminate(((wtgo_encryptor *)encryptor)->handle);
+	free(encryptor);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_encryptor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config) {
+	wtgo_encryptor *encryptor = calloc(1, sizeof(wtgo_encryptor));
+	if (encryptor == NULL) {
+		return ENOMEM;
+	}
+
+	encryptor->iface.encrypt = wtgo_encryptor_encrypt;
+	encryptor->iface.decrypt = wtgo_encryptor_decrypt;
+	encryptor->iface.sizing = wtgo_encryptor_sizing;
+	encryptor->iface.customize = wtgo_encryptor_customize;
+	encryptor->iface.terminate = wtgo_encryptor_terminate;
+	encryptor->api = connection->get_extension_api(connection);
+	encryptor->handle = handle;
+
+	int ret = connection->add_encryptor(connection, name, &encryptor->iface, config);
+	if (ret != 0) {
+		free(encryptor);
+	}
+
+	return ret;
+}
@@ -0,0 +1,256 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_encryptor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config);
+*/
+import (
+	"C"
+)
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// KeyProvider looks up the key for a keyid given in an encryption= config,
+// for example by asking an external key service.
+type KeyProvider interface {
+	Key(keyID string) ([]byte, error)
+}
+
+// KeyMap is a KeyProvider backed by a fixed set of keys.
+type KeyMap map[string][]byte
+
+func (m KeyMap) Key(keyID string) ([]byte, error) {
+	key, ok := m[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown keyid '%s'", keyID)
+	}
+
+	return key, nil
+}
+
+// BlockCipher encrypts and decrypts blocks with a single key. Its methods
+// are called from WiredTiger threads and must be safe for concurrent use.
+// The src and dst slices point into WiredTiger's buffers and must not be
+// retained.
+type BlockCipher interface {
+	// Encrypt writes the encrypted form of src into dst, which has room
+	// for len(src)+Overhead() bytes, and returns its length.
+	Encrypt(dst, src []byte) (int, error)
+
+	// Decrypt writes the decrypted form of src into dst and returns its
+	// length.
+	Decrypt(dst, src []byte) (int, error)
+
+	// Overhead is the maximum number of bytes Encrypt adds to a block.
+	Overhead() int
+}
+
+type Encryptor struct {
+	Keys KeyProvider
+
+	// NewCipher is called once for each keyid the first time it is used.
+	NewCipher func(key []byte) (BlockCipher, error)
+}
+
+type encryptor struct {
+	Encryptor
+	cipher BlockCipher
+}
+
+// AddEncryptor registers e under name so that objects can be created with
+// "encryption=(name=<name>,keyid=<keyid>)". To encrypt the log and metadata
+// with the connection's encryption= config, call it from
+// OpenOptions.EarlyLoad instead of after Open.
+func (conn *Connection) AddEncryptor(name string, e Encryptor) error {
+	if e.Keys == nil || e.NewCipher == nil {
+		return fmt.Errorf("encryptor %s needs Keys and NewCipher", name)
+	}
+
+	h := cgo.NewHandle(&encryptor{Encryptor: e})
+
+	namecstr := C.CString(name)
+	defer C.free(unsafe.Pointer(namecstr))
+
+	if code := int(C.wiredtiger_connection_add_encryptor(conn.wtc, namecstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+func encryptorCipher(handle C.uintptr_t) (BlockCipher, error) {
+	e := cgo.Handle(handle).Value().(*encryptor)
+	if e.cipher == nil {
+		return nil, fmt.Errorf("encryptor used without a keyid")
+	}
+
+	return e.cipher, nil
+}
+
+//export wtgoEncryptorEncrypt
+func wtgoEncryptorEncrypt(handle C.uintptr_t, src *C.uint8_t, srcLen C.size_t, dst *C.uint8_t, dstLen C.size_t, resultLen *C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		c, err := encryptorCipher(handle)
+		if err != nil {
+			return err
+		}
+
+		n, err := c.Encrypt(cBytes(dst, dstLen), cBytes(src, srcLen))
+		if err != nil {
+			return err
+		}
+
+		*resultLen = C.size_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEncryptorDecrypt
+func wtgoEncryptorDecrypt(handle C.uintptr_t, src *C.uint8_t, srcLen C.size_t, dst *C.uint8_t, dstLen C.size_t, resultLen *C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		c, err := encryptorCipher(handle)
+		if err != nil {
+			return err
+		}
+
+		n, err := c.Decrypt(cBytes(dst, dstLen), cBytes(src, srcLen))
+		if err != nil {
+			return err
+		}
+
+		*resultLen = C.size_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEncryptorSizing
+func wtgoEncryptorSizing(handle C.uintptr_t, expansion *C.size_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		c, err := encryptorCipher(handle)
+		if err != nil {
+			return err
+		}
+
+		*expansion = C.size_t(c.Overhead())
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEncryptorCustomize
+func wtgoEncryptorCustomize(handle C.uintptr_t, keyIDPtr *C.char, keyIDLen C.size_t, custom *C.uintptr_t) C.int {
+	e := cgo.Handle(handle).Value().(*encryptor)
+
+	keyID := C.GoStringN(keyIDPtr, C.int(keyIDLen))
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		key, err := e.Keys.Key(keyID)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", keyID, err)
+		}
+
+		c, err := e.NewCipher(key)
+		if err != nil {
+			return fmt.Errorf("cipher for key %s: %w", keyID, err)
+		}
+
+		*custom = C.uintptr_t(cgo.NewHandle(&encryptor{Encryptor: e.Encryptor, cipher: c}))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEncryptorTerminate
+func wtgoEncryptorTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher returns a BlockCipher that seals each block with AES-GCM
+// under a random nonce stored in front of the ciphertext. The key must be
+// 16, 24 or 32 bytes long.
+func NewAESGCMCipher(key []byte) (BlockCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return aesGCM{aead: aead}, nil
+}
+
+func (a aesGCM) Encrypt(dst, src []byte) (int, error) {
+	if len(dst) < len(src)+a.Overhead() {
+		return 0, fmt.Errorf("encrypt: destination of %d bytes is too small for %d bytes", len(dst), len(src))
+	}
+
+	n := a.aead.NonceSize()
+
+	nonce := dst[:n]
+
+	if _, err := rand.Read(nonce); err != nil {
+		return 0, fmt.Errorf("read nonce: %w", err)
+	}
+
+	sealed := a.aead.Seal(dst[n:n], nonce, src, nil)
+
+	return n + len(sealed), nil
+}
+
+func (a aesGCM) Decrypt(dst, src []byte) (int, error) {
+	n := a.aead.NonceSize()
+
+	if len(src) < a.Overhead() {
+		return 0, fmt.Errorf("decrypt: block of %d bytes is too short", len(src))
+	}
+
+	if len(dst) < len(src)-a.Overhead() {
+		return 0, fmt.Errorf("decrypt: destination of %d bytes is too small for %d bytes", len(dst), len(src))
+	}
+
+	opened, err := a.aead.Open(dst[:0], src[:n], src[n:], nil)
+	if err != nil {
+		return 0, fmt.Errorf("decrypt: %w", err)
+	}
+
+	return len(opened), nil
+}
+
+func (a aesGCM) Overhead() int {
+	return a.aead.NonceSize() + a.aead.Overhead()
+}
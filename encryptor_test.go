@@ -0,0 +1,265 @@
+package wtgo_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dylrich/wtgo"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testKeys = wtgo.KeyMap{
+	"k1": bytes.Repeat([]byte{0x11}, 32),
+	"k2": bytes.Repeat([]byte{0x22}, 32),
+}
+
+const plaintextMarker = "plaintext-marker-"
+
+func writeMarkedRows(session *wtgo.Session, tablename string, rows int) error {
+	cursor, err := session.OpenCursor(tablename, "")
+	if err != nil {
+		return fmt.Errorf("open cursor: %w", err)
+	}
+
+	defer cursor.Close()
+
+	for i := 0; i < rows; i++ {
+		if err := insert(cursor, fmt.Sprintf("%s%06d", plaintextMarker, i), strings.Repeat(plaintextMarker, 8)); err != nil {
+			return fmt.Errorf("insert %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func countMarkedRows(session *wtgo.Session, tablename string) (int, error) {
+	cursor, err := session.OpenCursor(tablename, "")
+	if err != nil {
+		return 0, fmt.Errorf("open cursor: %w", err)
+	}
+
+	defer cursor.Close()
+
+	var n int
+
+	for ; cursor.Next(); n++ {
+		var k, v string
+
+		if err := cursor.GetKey(&k); err != nil {
+			return 0, fmt.Errorf("get key: %w", err)
+		}
+
+		if err := cursor.GetValue(&v); err != nil {
+			return 0, fmt.Errorf("get value: %w", err)
+		}
+
+		if want := fmt.Sprintf("%s%06d", plaintextMarker, n); k != want {
+			return 0, fmt.Errorf("got key %s, wanted %s", k, want)
+		}
+	}
+
+	return n, cursor.Err()
+}
+
+// plaintextFiles returns the files under dir whose name matches pattern and
+// whose contents include plaintextMarker.
+func plaintextFiles(dir, pattern string) ([]string, error) {
+	var found []string
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if ok, err := filepath.Match(pattern, d.Name()); err != nil || !ok {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if bytes.Contains(data, []byte(plaintextMarker)) {
+			found = append(found, d.Name())
+		}
+
+		return nil
+	})
+
+	return found, err
+}
+
+func TestTableEncryptor(t *testing.T) {
+	if testFileSystem != "" {
+		t.Skip("scans the database files on disk")
+	}
+
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	encryptor := wtgo.Encryptor{Keys: testKeys, NewCipher: wtgo.NewAESGCMCipher}
+
+	if err := env.conn.AddEncryptor("goaes", encryptor); err != nil {
+		t.Fatalf("add encryptor: %s", err)
+	}
+
+	if err := env.session.Create("table:encrypted", "key_format=S,value_format=S,encryption=(name=goaes,keyid=k1)"); err != nil {
+		t.Fatalf("create encrypted table: %s", err)
+	}
+
+	if err := env.session.Create("table:plain", "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create plain table: %s", err)
+	}
+
+	if err := env.session.Create("table:unknown-key", "key_format=S,value_format=S,encryption=(name=goaes,keyid=k3)"); err == nil {
+		t.Fatalf("created table with unknown keyid")
+	}
+
+	for _, tablename := range []string{"table:encrypted", "table:plain"} {
+		if err := writeMarkedRows(env.session, tablename, 1000); err != nil {
+			t.Fatalf("write %s: %s", tablename, err)
+		}
+	}
+
+	if err := env.conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	found, err := plaintextFiles(env.dir, "*.wt")
+	if err != nil {
+		t.Fatalf("scan files: %s", err)
+	}
+
+	if len(found) != 1 || found[0] != "plain.wt" {
+		t.Fatalf("plaintext found in %v, wanted only plain.wt", found)
+	}
+
+	conn, err := wtgo.Open(env.dir, "")
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	if err := conn.AddEncryptor("goaes", encryptor); err != nil {
+		t.Fatalf("add encryptor after reopen: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	n, err := countMarkedRows(session, "table:encrypted")
+	if err != nil {
+		t.Fatalf("read encrypted table: %s", err)
+	}
+
+	if n != 1000 {
+		t.Fatalf("read %d rows after reopen, wanted 1000", n)
+	}
+}
+
+func TestConnectionEncryptor(t *testing.T) {
+	if testFileSystem != "" {
+		t.Skip("scans the database files on disk")
+	}
+
+	dir, err := os.MkdirTemp(os.TempDir(), "test-encryptor-*")
+	if err != nil {
+		t.Fatalf("make temp dir: %s", err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	opts := wtgo.OpenOptions{
+		EarlyLoad: []func(*wtgo.Connection) error{
+			func(conn *wtgo.Connection) error {
+				return conn.AddEncryptor("goaes", wtgo.Encryptor{Keys: testKeys, NewCipher: wtgo.NewAESGCMCipher})
+			},
+		},
+	}
+
+	config := "create,log=(enabled),encryption=(name=goaes,keyid=k1)"
+
+	conn, err := wtgo.OpenWithOptions(dir, config, opts)
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	// Tables inherit the connection's encryptor; table:other overrides the
+	// keyid.
+	if err := session.Create("table:"+plaintextMarker+"table", "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	if err := session.Create("table:other", "key_format=S,value_format=S,encryption=(name=goaes,keyid=k2)"); err != nil {
+		t.Fatalf("create table with own keyid: %s", err)
+	}
+
+	for _, tablename := range []string{"table:" + plaintextMarker + "table", "table:other"} {
+		if err := writeMarkedRows(session, tablename, 1000); err != nil {
+			t.Fatalf("write %s: %s", tablename, err)
+		}
+	}
+
+	if err := session.Checkpoint(""); err != nil {
+		t.Fatalf("checkpoint: %s", err)
+	}
+
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	// The table name is in the metadata and the log, so neither may
+	// contain it in the clear.
+	for _, pattern := range []string{"*.wt", "WiredTigerLog.*"} {
+		found, err := plaintextFiles(dir, pattern)
+		if err != nil {
+			t.Fatalf("scan files: %s", err)
+		}
+
+		if len(found) != 0 {
+			t.Fatalf("plaintext found in %v", found)
+		}
+	}
+
+	if _, err := wtgo.Open(dir, ""); err == nil {
+		t.Fatalf("reopened encrypted database without its encryptor")
+	}
+
+	conn, err = wtgo.OpenWithOptions(dir, config, opts)
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	session, err = conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session after reopen: %s", err)
+	}
+
+	for _, tablename := range []string{"table:" + plaintextMarker + "table", "table:other"} {
+		n, err := countMarkedRows(session, tablename)
+		if err != nil {
+			t.Fatalf("read %s: %s", tablename, err)
+		}
+
+		if n != 1000 {
+			t.Fatalf("read %d rows of %s after reopen, wanted 1000", n, tablename)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <stdlib.h>
+
+typedef struct {
+	WT_EVENT_HANDLER iface;
+	uintptr_t handle;
+} wtgo_event_handler;
+
+static int wtgo_event_handler_error(WT_EVENT_HANDLER *handler, WT_SESSION *session, int error, const char *message) {
+	return wtgoEventHandlerError(((wtgo_event_handler *)handler)->handle, error, (char *)message);
+}
+
+static int wtgo_event_handler_message(WT_EVENT_HANDLER *handler, WT_SESSION *session, const char *message) {
+	return wtgoEventHandlerMessage(((wtgo_event_handler *)handler)->handle, (char *)message);
+}
+
+static int wtgo_event_handler_progress(WT_EVENT_HANDLER *handler, WT_SESSION *session, const char *operation, uint64_t progress) {
+	return wtgoEventHandlerProgress(((wtgo_event_handler *)handler)->handle, (char *)operation, progress);
+}
+
+static int wtgo_event_handler_close(WT_EVENT_HANDLER *handler, WT_SESSION *session, WT_CURSOR *cursor) {
+	return wtgoEventHandlerClose(((wtgo_event_handler *)handler)->handle, cursor == NULL ? NULL : (char *)cursor->uri);
+}
+
+static int wtgo_event_handler_general(WT_EVENT_HANDLER *handler, WT_CONNECTION *wt_conn, WT_SESSION *session, WT_EVENT_TYPE type, void *arg) {
+	return wtgoEventHandlerGeneral(((wtgo_event_handler *)handler)->handle, (int)type);
+}
+
+WT_EVENT_HANDLER *wtgo_event_handler_new(uintptr_t handle) {
+	wtgo_event_handler *handler = calloc(1, sizeof(wtgo_event_handler));
+	if (handler == NULL) {
+		return NULL;
+	}
+
+	handler->iface.handle_error = wtgo_event_handler_error;
+	handler->iface.handle_message = wtgo_event_handler_message;
+	handler->iface.handle_progress = wtgo_event_handler_progress;
+	handler->iface.handle_close = wtgo_event_handler_close;
+	handler->iface.handle_general = wtgo_event_handler_general;
+	handler->handle = handle;
+
+	return &handler->iface;
+}
+
+uintptr_t wtgo_event_handler_free(WT_EVENT_HANDLER *handler) {
+	uintptr_t handle = ((wtgo_event_handler *)handler)->handle;
+	free(handler);
+
+	return handle;
+}
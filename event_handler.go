@@ -0,0 +1,228 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+WT_EVENT_HANDLER *wtgo_event_handler_new(uintptr_t handle);
+uintptr_t wtgo_event_handler_free(WT_EVENT_HANDLER *handler);
+*/
+import (
+	"C"
+)
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime/cgo"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+type EventType int
+
+const (
+	EventCompactCheck EventType = C.WT_EVENT_COMPACT_CHECK
+	EventConnClose    EventType = C.WT_EVENT_CONN_CLOSE
+	EventConnReady    EventType = C.WT_EVENT_CONN_READY
+)
+
+func (e EventType) String() string {
+	switch e {
+	case EventCompactCheck:
+		return "compact_check"
+	case EventConnClose:
+		return "conn_close"
+	case EventConnReady:
+		return "conn_ready"
+	default:
+		return fmt.Sprintf("event(%d)", int(e))
+	}
+}
+
+// EventHandler receives WiredTiger's diagnostics instead of stderr. Its
+// methods are called from WiredTiger threads, possibly concurrently, and
+// must not call back into the connection or session that raised the event.
+// Embed NopEventHandler to implement only some of them.
+type EventHandler interface {
+	HandleError(code ErrorCode, message string)
+	HandleMessage(message string)
+	HandleProgress(operation string, progress uint64)
+
+	// HandleClose is called when WiredTiger closes a handle the application
+	// still holds, e.g. when the connection is closed with sessions or
+	// cursors open. uri is empty for sessions.
+	HandleClose(uri string)
+
+	// HandleGeneral is called for other events. Returning an error from
+	// EventCompactCheck interrupts the compaction.
+	HandleGeneral(event EventType) error
+}
+
+type NopEventHandler struct{}
+
+func (NopEventHandler) HandleError(code ErrorCode, message string)       {}
+func (NopEventHandler) HandleMessage(message string)                     {}
+func (NopEventHandler) HandleProgress(operation string, progress uint64) {}
+func (NopEventHandler) HandleClose(uri string)                           {}
+func (NopEventHandler) HandleGeneral(event EventType) error              { return nil }
+
+func newEventHandler(h EventHandler) (*C.WT_EVENT_HANDLER, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	handle := cgo.NewHandle(h)
+
+	eh := C.wtgo_event_handler_new(C.uintptr_t(handle))
+	if eh == nil {
+		handle.Delete()
+		return nil, fmt.Errorf("allocate event handler: %w", syscall.ENOMEM)
+	}
+
+	return eh, nil
+}
+
+func freeEventHandler(eh *C.WT_EVENT_HANDLER) {
+	if eh == nil {
+		return
+	}
+
+	cgo.Handle(C.wtgo_event_handler_free(eh)).Delete()
+}
+
+func eventHandler(handle C.uintptr_t) EventHandler {
+	return cgo.Handle(handle).Value().(EventHandler)
+}
+
+// The handlers below return non-zero only when the Go handler panics, which
+// makes WiredTiger fall back to writing the event to stderr.
+
+//export wtgoEventHandlerError
+func wtgoEventHandlerError(handle C.uintptr_t, code C.int, message *C.char) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		eventHandler(handle).HandleError(ErrorCode(code), C.GoString(message))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEventHandlerMessage
+func wtgoEventHandlerMessage(handle C.uintptr_t, message *C.char) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		eventHandler(handle).HandleMessage(C.GoString(message))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEventHandlerProgress
+func wtgoEventHandlerProgress(handle C.uintptr_t, operation *C.char, progress C.uint64_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		eventHandler(handle).HandleProgress(C.GoString(operation), uint64(progress))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEventHandlerClose
+func wtgoEventHandlerClose(handle C.uintptr_t, uri *C.char) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		var u string
+		if uri != nil {
+			u = C.GoString(uri)
+		}
+
+		eventHandler(handle).HandleClose(u)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoEventHandlerGeneral
+func wtgoEventHandlerGeneral(handle C.uintptr_t, event C.int) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return eventHandler(handle).HandleGeneral(EventType(event))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+// SlogEventHandler writes WiredTiger events to Logger. Messages WiredTiger
+// writes as JSON (json_output=[message] in the open config) are split into
+// attributes.
+type SlogEventHandler struct {
+	Logger *slog.Logger
+}
+
+func (h SlogEventHandler) HandleError(code ErrorCode, message string) {
+	msg, attrs := messageAttrs(message)
+	attrs = append(attrs, slog.Int("code", int(code)), slog.String("error", code.Error()))
+
+	h.Logger.LogAttrs(context.Background(), slog.LevelError, msg, attrs...)
+}
+
+func (h SlogEventHandler) HandleMessage(message string) {
+	msg, attrs := messageAttrs(message)
+	h.Logger.LogAttrs(context.Background(), slog.LevelInfo, msg, attrs...)
+}
+
+func (h SlogEventHandler) HandleProgress(operation string, progress uint64) {
+	h.Logger.LogAttrs(context.Background(), slog.LevelInfo, "progress", slog.String("operation", operation), slog.Uint64("progress", progress))
+}
+
+func (h SlogEventHandler) HandleClose(uri string) {
+	h.Logger.LogAttrs(context.Background(), slog.LevelDebug, "handle closed", slog.String("uri", uri))
+}
+
+func (h SlogEventHandler) HandleGeneral(event EventType) error {
+	h.Logger.LogAttrs(context.Background(), slog.LevelDebug, "event", slog.String("type", event.String()))
+	return nil
+}
+
+func messageAttrs(message string) (string, []slog.Attr) {
+	message = strings.TrimSpace(message)
+
+	var fields map[string]any
+	if !strings.HasPrefix(message, "{") || json.Unmarshal([]byte(message), &fields) != nil {
+		return message, nil
+	}
+
+	msg, _ := fields["msg"].(string)
+	delete(fields, "msg")
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+
+	return msg, attrs
+}
@@ -0,0 +1,185 @@
+package wtgo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/dylrich/wtgo"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingEventHandler struct {
+	wtgo.NopEventHandler
+
+	mu       sync.Mutex
+	errors   []string
+	messages []string
+	closed   []string
+}
+
+func (r *recordingEventHandler) HandleError(code wtgo.ErrorCode, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = append(r.errors, message)
+}
+
+func (r *recordingEventHandler) HandleMessage(message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, message)
+}
+
+func (r *recordingEventHandler) HandleClose(uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = append(r.closed, uri)
+}
+
+func (r *recordingEventHandler) snapshot() (errors, messages, closed []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.errors), slices.Clone(r.messages), slices.Clone(r.closed)
+}
+
+func containsSubstring(s []string, substr string) bool {
+	return slices.ContainsFunc(s, func(v string) bool { return strings.Contains(v, substr) })
+}
+
+func TestEventHandler(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "test-events-*")
+	if err != nil {
+		t.Fatalf("make temp dir: %s", err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	connEvents := &recordingEventHandler{}
+
+	conn, err := openTestConnection(dir, "create,verbose=[checkpoint]", wtgo.OpenOptions{EventHandler: connEvents})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	sessionEvents := &recordingEventHandler{}
+
+	own, err := conn.OpenSessionWithOptions("", wtgo.SessionOptions{EventHandler: sessionEvents})
+	if err != nil {
+		t.Fatalf("open session with handler: %s", err)
+	}
+
+	if err := session.Create("table:events", "key_format=S,value_format=S,no_such_setting=1"); err == nil {
+		t.Fatalf("create with invalid config succeeded")
+	}
+
+	if err := own.Create("table:events", "key_format=Z"); err == nil {
+		t.Fatalf("create with invalid format succeeded")
+	}
+
+	if err := session.Checkpoint(""); err != nil {
+		t.Fatalf("checkpoint: %s", err)
+	}
+
+	if err := own.Close(""); err != nil {
+		t.Fatalf("close session: %s", err)
+	}
+
+	if err := session.Create("table:events", "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	if _, err := session.OpenCursor("table:events", ""); err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	// The cursor and session left open are closed by the connection.
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close: %s", err)
+	}
+
+	connErrors, connMessages, connClosed := connEvents.snapshot()
+	sessionErrors, _, _ := sessionEvents.snapshot()
+
+	if !containsSubstring(connErrors, "no_such_setting") || containsSubstring(connErrors, "'Z'") {
+		t.Fatalf("connection handler got errors %q, wanted only the one about no_such_setting", connErrors)
+	}
+
+	if !containsSubstring(sessionErrors, "'Z'") || containsSubstring(sessionErrors, "no_such_setting") {
+		t.Fatalf("session handler got errors %q, wanted only the one about format Z", sessionErrors)
+	}
+
+	if len(connMessages) == 0 {
+		t.Fatalf("connection handler got no verbose checkpoint messages")
+	}
+
+	if !slices.Contains(connClosed, "table:events") {
+		t.Fatalf("connection handler was not told about the open cursor closing: %q", connClosed)
+	}
+}
+
+func TestSlogEventHandler(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "test-events-*")
+	if err != nil {
+		t.Fatalf("make temp dir: %s", err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	conn, err := openTestConnection(dir, "create,json_output=[error,message]", wtgo.OpenOptions{EventHandler: wtgo.SlogEventHandler{Logger: logger}})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	if err := session.Create("table:events", "key_format=S,value_format=S,no_such_setting=1"); err == nil {
+		t.Fatalf("create with invalid config succeeded")
+	}
+
+	var found bool
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("decode log line %q: %s", line, err)
+		}
+
+		if record["level"] != "ERROR" {
+			continue
+		}
+
+		if _, ok := record["code"].(float64); !ok {
+			t.Fatalf("error record has no code: %v", record)
+		}
+
+		if bytes.Contains(line, []byte("no_such_setting")) {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("no error record about no_such_setting in:\n%s", buf.String())
+	}
+}
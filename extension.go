@@ -0,0 +1,137 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_load_extension(WT_CONNECTION *connection, const char *path, const char *config) {
+	return connection->load_extension(connection, path, config);
+}
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtconfig"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// Extension is a shared library implementing WiredTiger extensions such as
+// compressors or collators.
+type Extension struct {
+	// Path is the shared library, or "local" to look the entry point up in
+	// the running binary.
+	Path string
+
+	// Entry is the initialization function, wiredtiger_extension_init by
+	// default, and Terminate the optional function called on close.
+	Entry     string
+	Terminate string
+
+	// Config is passed to Entry as its WT_CONFIG_ARG.
+	Config string
+
+	// EarlyLoad loads the extension at the start of wiredtiger_open. It is
+	// only valid in OpenOptions.Extensions.
+	EarlyLoad bool
+}
+
+func (e Extension) config() string {
+	var parts []string
+
+	if e.Entry != "" {
+		parts = append(parts, "entry="+e.Entry)
+	}
+
+	if e.Terminate != "" {
+		parts = append(parts, "terminate="+e.Terminate)
+	}
+
+	if e.Config != "" {
+		parts = append(parts, "config=("+e.Config+")")
+	}
+
+	if e.EarlyLoad {
+		parts = append(parts, "early_load=true")
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// check catches a missing library before WiredTiger reports it as a bare
+// error code.
+func (e Extension) check() error {
+	if e.Path == "local" {
+		return nil
+	}
+
+	if _, err := os.Stat(e.Path); err != nil {
+		return fmt.Errorf("extension %s: %w", e.Path, err)
+	}
+
+	return nil
+}
+
+func (conn *Connection) LoadExtension(ext Extension) error {
+	if ext.EarlyLoad {
+		return fmt.Errorf("extension %s: early load is only possible when opening the connection", ext.Path)
+	}
+
+	if err := ext.check(); err != nil {
+		return err
+	}
+
+	pathcstr := C.CString(ext.Path)
+	defer C.free(unsafe.Pointer(pathcstr))
+
+	var configcstr *C.char
+
+	if c := ext.config(); c != "" {
+		configcstr = C.CString(c)
+		defer C.free(unsafe.Pointer(configcstr))
+	}
+
+	if code := int(C.wiredtiger_connection_load_extension(conn.wtc, pathcstr, configcstr)); code != 0 {
+		return fmt.Errorf("load extension %s: %w", ext.Path, ErrorCode(code))
+	}
+
+	return nil
+}
+
+// extensionsConfig adds exts to the extensions= list of a wiredtiger_open
+// config.
+func extensionsConfig(config string, exts []Extension) (string, error) {
+	for _, ext := range exts {
+		if err := ext.check(); err != nil {
+			return "", err
+		}
+
+		item := wtconfig.Quote(ext.Path)
+		if c := ext.config(); c != "" {
+			item += "=(" + c + ")"
+		}
+
+		c, err := appendExtension(config, item)
+		if err != nil {
+			return "", err
+		}
+
+		config = c
+	}
+
+	return config, nil
+}
+
+// appendExtension adds ext, an item of the extensions= list, to config.
+func appendExtension(config, ext string) (string, error) {
+	c, err := wtconfig.AppendList(config, "extensions", ext)
+	if err != nil {
+		return "", fmt.Errorf("add extension to config: %w", err)
+	}
+
+	return c, nil
+}
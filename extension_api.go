@@ -0,0 +1,177 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_extension_config_get(WT_EXTENSION_API *api, WT_SESSION *session, WT_CONFIG_ARG *config, const char *key, WT_CONFIG_ITEM *value) {
+	return api->config_get(api, session, config, key, value);
+}
+
+int wiredtiger_extension_config_parser_open_arg(WT_EXTENSION_API *api, WT_SESSION *session, WT_CONFIG_ARG *config, WT_CONFIG_PARSER **parserp) {
+	return api->config_parser_open_arg(api, session, config, parserp);
+}
+
+int wiredtiger_config_parser_next(WT_CONFIG_PARSER *parser, WT_CONFIG_ITEM *key, WT_CONFIG_ITEM *value) {
+	return parser->next(parser, key, value);
+}
+
+int wiredtiger_config_parser_close(WT_CONFIG_PARSER *parser) {
+	return parser->close(parser);
+}
+
+int wiredtiger_extension_metadata_search(WT_EXTENSION_API *api, WT_SESSION *session, const char *key, char **valuep) {
+	return api->metadata_search(api, session, key, valuep);
+}
+
+int wiredtiger_extension_metadata_insert(WT_EXTENSION_API *api, WT_SESSION *session, const char *key, const char *value) {
+	return api->metadata_insert(api, session, key, value);
+}
+
+int wiredtiger_extension_metadata_remove(WT_EXTENSION_API *api, WT_SESSION *session, const char *key) {
+	return api->metadata_remove(api, session, key);
+}
+*/
+import (
+	"C"
+)
+
+import (
+	"strings"
+	"unsafe"
+)
+
+// extensionAPI wraps the WT_EXTENSION_API calls made from Go callbacks on
+// behalf of the session WiredTiger passed in.
+type extensionAPI struct {
+	api     *C.WT_EXTENSION_API
+	session *C.WT_SESSION
+}
+
+func configItem(item *C.WT_CONFIG_ITEM) string {
+	return C.GoStringN(item.str, C.int(item.len))
+}
+
+func (e extensionAPI) configGet(config *C.WT_CONFIG_ARG, key string) (string, bool, error) {
+	var item C.WT_CONFIG_ITEM
+
+	keycstr := C.CString(key)
+	defer C.free(unsafe.Pointer(keycstr))
+
+	code := int(C.wiredtiger_extension_config_get(e.api, e.session, config, keycstr, &item))
+	if code == int(ErrNotFound) {
+		return "", false, nil
+	}
+
+	if code != 0 {
+		return "", false, ErrorCode(code)
+	}
+
+	return configItem(&item), true, nil
+}
+
+func (e extensionAPI) configBool(config *C.WT_CONFIG_ARG, key string) (bool, error) {
+	var item C.WT_CONFIG_ITEM
+
+	keycstr := C.CString(key)
+	defer C.free(unsafe.Pointer(keycstr))
+
+	code := int(C.wiredtiger_extension_config_get(e.api, e.session, config, keycstr, &item))
+	if code == int(ErrNotFound) {
+		return false, nil
+	}
+
+	if code != 0 {
+		return false, ErrorCode(code)
+	}
+
+	return item.val != 0, nil
+}
+
+// configItemText returns item as it was written in the configuration. The
+// parser strips the quotes from strings but leaves escapes in place, so
+// quoting the text again restores it.
+func configItemText(item *C.WT_CONFIG_ITEM) string {
+	if item._type == C.WT_CONFIG_ITEM_STRING {
+		return "\"" + configItem(item) + "\""
+	}
+
+	return configItem(item)
+}
+
+// configString rebuilds the application's configuration string from
+// config, which WiredTiger only hands to extensions in parsed form.
+func (e extensionAPI) configString(config *C.WT_CONFIG_ARG) (string, error) {
+	var parser *C.WT_CONFIG_PARSER
+
+	if code := int(C.wiredtiger_extension_config_parser_open_arg(e.api, e.session, config, &parser)); code != 0 {
+		return "", ErrorCode(code)
+	}
+
+	defer C.wiredtiger_config_parser_close(parser)
+
+	var parts []string
+
+	for {
+		var key, value C.WT_CONFIG_ITEM
+
+		code := int(C.wiredtiger_config_parser_next(parser, &key, &value))
+		if code == int(ErrNotFound) {
+			break
+		}
+
+		if code != 0 {
+			return "", ErrorCode(code)
+		}
+
+		parts = append(parts, configItemText(&key)+"="+configItemText(&value))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+func (e extensionAPI) metadata(key string) (string, bool, error) {
+	var value *C.char
+
+	keycstr := C.CString(key)
+	defer C.free(unsafe.Pointer(keycstr))
+
+	code := int(C.wiredtiger_extension_metadata_search(e.api, e.session, keycstr, &value))
+	if code == int(ErrNotFound) {
+		return "", false, nil
+	}
+
+	if code != 0 {
+		return "", false, ErrorCode(code)
+	}
+
+	defer C.free(unsafe.Pointer(value))
+
+	return C.GoString(value), true, nil
+}
+
+func (e extensionAPI) insertMetadata(key, value string) error {
+	keycstr := C.CString(key)
+	defer C.free(unsafe.Pointer(keycstr))
+
+	valuecstr := C.CString(value)
+	defer C.free(unsafe.Pointer(valuecstr))
+
+	if code := int(C.wiredtiger_extension_metadata_insert(e.api, e.session, keycstr, valuecstr)); code != 0 {
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+func (e extensionAPI) removeMetadata(key string) error {
+	keycstr := C.CString(key)
+	defer C.free(unsafe.Pointer(keycstr))
+
+	code := int(C.wiredtiger_extension_metadata_remove(e.api, e.session, keycstr))
+	if code != 0 && ErrorCode(code) != ErrNotFound {
+		return ErrorCode(code)
+	}
+
+	return nil
+}
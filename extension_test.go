@@ -0,0 +1,147 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// buildTestExtension compiles testdata/extension/reverse.c into a shared
+// library, using CGO_CFLAGS to find wiredtiger.h.
+func buildTestExtension(t *testing.T) string {
+	t.Helper()
+
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "cc"
+	}
+
+	if _, err := exec.LookPath(cc); err != nil {
+		t.Skipf("no C compiler: %s", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "reverse.so")
+
+	args := strings.Fields(os.Getenv("CGO_CFLAGS"))
+	args = append(args, "-shared", "-fPIC", "-o", out, filepath.Join("testdata", "extension", "reverse.c"))
+
+	if b, err := exec.Command(cc, args...).CombinedOutput(); err != nil {
+		t.Fatalf("build test extension: %s\n%s", err, b)
+	}
+
+	return out
+}
+
+func reverseCollatedKeys(session *wtgo.Session) ([]string, error) {
+	if err := session.Create("table:reversed", "key_format=S,value_format=S,collator=reverse"); err != nil {
+		return nil, err
+	}
+
+	cursor, err := session.OpenCursor("table:reversed", "")
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close()
+
+	for _, k := range []string{"b", "a", "c"} {
+		if err := insert(cursor, k, k); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cursor.Reset(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+
+	for cursor.Next() {
+		var k string
+
+		if err := cursor.GetKey(&k); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, cursor.Err()
+}
+
+func TestLoadExtension(t *testing.T) {
+	path := buildTestExtension(t)
+
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	missing := filepath.Join(t.TempDir(), "missing.so")
+
+	if err := env.conn.LoadExtension(wtgo.Extension{Path: missing}); err == nil || !strings.Contains(err.Error(), missing) {
+		t.Fatalf("load missing extension returned err '%v', expected an error naming %s", err, missing)
+	}
+
+	err = env.conn.LoadExtension(wtgo.Extension{Path: path, Entry: "wtgo_test_extension_init", Config: "fail=true"})
+	if !errors.Is(err, wtgo.ErrorCode(syscall.EINVAL)) || !strings.Contains(err.Error(), path) {
+		t.Fatalf("load failing extension returned err '%v', expected EINVAL naming %s", err, path)
+	}
+
+	if err := env.conn.LoadExtension(wtgo.Extension{Path: path, Entry: "wtgo_test_extension_init", EarlyLoad: true}); err == nil {
+		t.Fatalf("early load after open succeeded")
+	}
+
+	if err := env.conn.LoadExtension(wtgo.Extension{Path: path, Entry: "wtgo_test_extension_init"}); err != nil {
+		t.Fatalf("load extension: %s", err)
+	}
+
+	keys, err := reverseCollatedKeys(env.session)
+	if err != nil {
+		t.Fatalf("use reverse collator: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"c", "b", "a"}, keys); diff != "" {
+		t.Fatalf("keys don't match (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenExtensions(t *testing.T) {
+	path := buildTestExtension(t)
+
+	dir := t.TempDir()
+
+	opts := wtgo.OpenOptions{
+		Extensions: []wtgo.Extension{{Path: path, Entry: "wtgo_test_extension_init", EarlyLoad: true}},
+	}
+
+	conn, err := openTestConnection(dir, "create", opts)
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	keys, err := reverseCollatedKeys(session)
+	if err != nil {
+		t.Fatalf("use reverse collator: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"c", "b", "a"}, keys); diff != "" {
+		t.Fatalf("keys don't match (-want +got):\n%s", diff)
+	}
+}
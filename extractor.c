@@ -0,0 +1,49 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+
+typedef struct {
+	WT_EXTRACTOR iface;
+	uintptr_t handle;
+} wtgo_extractor;
+
+static int wtgo_extractor_extract(WT_EXTRACTOR *extractor, WT_SESSION *session, const WT_ITEM *key, const WT_ITEM *value, WT_CURSOR *result_cursor) {
+	return wtgoExtractorExtract(((wtgo_extractor *)extractor)->handle, (void *)key->data, key->size, (void *)value->data, value->size, result_cursor);
+}
+
+static int wtgo_extractor_terminate(WT_EXTRACTOR *extractor, WT_SESSION *session) {
+	wtgoExtractorTerminate(((wtgo_extractor *)extractor)->handle);
+	free(extractor);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_extractor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config) {
+	wtgo_extractor *extractor = calloc(1, sizeof(wtgo_extractor));
+	if (extractor == NULL) {
+		return ENOMEM;
+	}
+
+	extractor->iface.extract = wtgo_extractor_extract;
+	extractor->iface.terminate = wtgo_extractor_terminate;
+	extractor->handle = handle;
+
+	int ret = connection->add_extractor(connection, name, &extractor->iface, config);
+	if (ret != 0) {
+		free(extractor);
+	}
+
+	return ret;
+}
+
+int wiredtiger_extractor_insert(WT_CURSOR *result_cursor, const void *packed_key, size_t key_size) {
+	WT_ITEM key;
+	key.data = packed_key;
+	key.size = key_size;
+
+	result_cursor->flags |= WT_CURSTD_RAW;
+	result_cursor->set_key(result_cursor, &key);
+
+	return result_cursor->insert(result_cursor);
+}
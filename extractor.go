@@ -0,0 +1,154 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_extractor(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config);
+int wiredtiger_extractor_insert(WT_CURSOR *result_cursor, const void *packed_key, size_t key_size);
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtformat"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Extractor produces the index keys for a row. It is called from
+// WiredTiger threads, possibly for several sessions at once, so Extract
+// must be safe for concurrent use.
+type Extractor struct {
+	// KeyFormat and ValueFormat describe the rows of the tables the
+	// extractor is used on, IndexKeyFormat the keys it emits. The index
+	// must be created with the same key_format.
+	KeyFormat      string
+	ValueFormat    string
+	IndexKeyFormat string
+
+	Extract func(key, value []any, emit func(indexKey ...any) error) error
+}
+
+type extractor struct {
+	keyPackers      []wtformat.FieldPacker
+	valuePackers    []wtformat.FieldPacker
+	indexKeyPackers []wtformat.FieldPacker
+	extract         func(key, value []any, emit func(indexKey ...any) error) error
+}
+
+// AddExtractor registers e under name so that indices can be created with
+// "extractor=<name>".
+func (conn *Connection) AddExtractor(name string, e Extractor) error {
+	if e.Extract == nil {
+		return fmt.Errorf("extractor %s has no Extract function", name)
+	}
+
+	keyPackers, err := wtformat.ParseFormat(e.KeyFormat)
+	if err != nil {
+		return fmt.Errorf("parse key format: %w", err)
+	}
+
+	valuePackers, err := wtformat.ParseFormat(e.ValueFormat)
+	if err != nil {
+		return fmt.Errorf("parse value format: %w", err)
+	}
+
+	indexKeyPackers, err := wtformat.ParseFormat(e.IndexKeyFormat)
+	if err != nil {
+		return fmt.Errorf("parse index key format: %w", err)
+	}
+
+	h := cgo.NewHandle(&extractor{
+		keyPackers:      keyPackers,
+		valuePackers:    valuePackers,
+		indexKeyPackers: indexKeyPackers,
+		extract:         e.Extract,
+	})
+
+	namecstr := C.CString(name)
+	defer C.free(unsafe.Pointer(namecstr))
+
+	if code := int(C.wiredtiger_connection_add_extractor(conn.wtc, namecstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+//export wtgoExtractorExtract
+func wtgoExtractorExtract(handle C.uintptr_t, key unsafe.Pointer, keySize C.size_t, value unsafe.Pointer, valueSize C.size_t, result *C.WT_CURSOR) C.int {
+	e := cgo.Handle(handle).Value().(*extractor)
+
+	err := e.run(C.GoBytes(key, C.int(keySize)), C.GoBytes(value, C.int(valueSize)), result)
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoExtractorTerminate
+func wtgoExtractorTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+func (e *extractor) run(key, value []byte, result *C.WT_CURSOR) (err error) {
+	defer recoverCallback(&err)
+
+	keys, err := unpackAll(e.keyPackers, key)
+	if err != nil {
+		return fmt.Errorf("unpack key: %w", err)
+	}
+
+	values, err := unpackAll(e.valuePackers, value)
+	if err != nil {
+		return fmt.Errorf("unpack value: %w", err)
+	}
+
+	var buf []byte
+
+	emit := func(indexKey ...any) error {
+		if len(indexKey) != len(e.indexKeyPackers) {
+			return fmt.Errorf("number of index keys does not match format")
+		}
+
+		buf = buf[:0]
+
+		for i, p := range e.indexKeyPackers {
+			b, err := p.PackField(indexKey[i], buf)
+			if err != nil {
+				return err
+			}
+
+			buf = b
+		}
+
+		// The extraction cursor's format carries a trailing pad byte so the
+		// primary key can be appended to the packed index key.
+		buf = append(buf, 0)
+
+		if code := int(C.wiredtiger_extractor_insert(result, unsafe.Pointer(&buf[0]), C.size_t(len(buf)))); code != 0 {
+			return ErrorCode(code)
+		}
+
+		return nil
+	}
+
+	return e.extract(keys, values, emit)
+}
+
+func unpackAll(packers []wtformat.FieldPacker, data []byte) ([]any, error) {
+	fields := make([]any, len(packers))
+
+	for i, p := range packers {
+		d, err := p.UnpackField(data, &fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("unpack field %d: %w", i, err)
+		}
+
+		data = d
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,137 @@
+package wtgo_test
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExtractor(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	tags := wtgo.Extractor{
+		KeyFormat:      "Q",
+		ValueFormat:    "SS",
+		IndexKeyFormat: "S",
+		Extract: func(key, value []any, emit func(indexKey ...any) error) error {
+			for _, tag := range strings.Split(value[1].(string), ",") {
+				if tag == "" {
+					continue
+				}
+
+				if err := emit(tag); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	if err := env.conn.AddExtractor("tags", tags); err != nil {
+		t.Fatalf("add extractor: %s", err)
+	}
+
+	tablename := "table:posts"
+
+	if err := env.session.Create(tablename, "key_format=Q,value_format=SS,columns=(id,title,tags)"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	if err := env.session.Create("index:posts:tags", "key_format=S,extractor=tags,columns=(tags)"); err != nil {
+		t.Fatalf("create index: %s", err)
+	}
+
+	records := []record{
+		{k: []any{uint64(1)}, v: []any{"cgo callbacks", "go,c"}},
+		{k: []any{uint64(2)}, v: []any{"untagged", ""}},
+		{k: []any{uint64(3)}, v: []any{"generics", "go"}},
+		{k: []any{uint64(4)}, v: []any{"btrees", "c,storage"}},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(records))
+
+	for i, r := range records {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			session, err := env.conn.OpenSession("")
+			if err != nil {
+				errs[i] = fmt.Errorf("open session: %w", err)
+				return
+			}
+
+			defer session.Close("")
+
+			cursor, err := session.OpenCursor(tablename, "")
+			if err != nil {
+				errs[i] = fmt.Errorf("open cursor: %w", err)
+				return
+			}
+
+			errs[i] = seed(cursor, []record{r})
+		}()
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("insert record %d: %s", i, err)
+		}
+	}
+
+	index, err := env.session.OpenCursor("index:posts:tags(id)", "")
+	if err != nil {
+		t.Fatalf("open index cursor: %s", err)
+	}
+
+	type entry struct {
+		Tag string
+		ID  uint64
+	}
+
+	got := make([]entry, 0, 5)
+
+	for index.Next() {
+		var e entry
+
+		if err := index.GetKey(&e.Tag); err != nil {
+			t.Fatalf("get key: %s", err)
+		}
+
+		if err := index.GetValue(&e.ID); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		got = append(got, e)
+	}
+
+	if err := index.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	want := []entry{
+		{Tag: "c", ID: 1},
+		{Tag: "c", ID: 4},
+		{Tag: "go", ID: 1},
+		{Tag: "go", ID: 3},
+		{Tag: "storage", ID: 4},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("index entries don't match (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,164 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+#include <string.h>
+
+typedef struct {
+	WT_FILE_SYSTEM iface;
+	uintptr_t handle;
+} wtgo_file_system;
+
+typedef struct {
+	WT_FILE_HANDLE iface;
+	uintptr_t handle;
+} wtgo_file_handle;
+
+static int wtgo_file_handle_close(WT_FILE_HANDLE *file_handle, WT_SESSION *session) {
+	int ret = wtgoFileHandleClose(((wtgo_file_handle *)file_handle)->handle);
+
+	free(file_handle->name);
+	free(file_handle);
+
+	return ret;
+}
+
+static int wtgo_file_handle_lock(WT_FILE_HANDLE *file_handle, WT_SESSION *session, bool lock) {
+	return wtgoFileHandleLock(((wtgo_file_handle *)file_handle)->handle, lock);
+}
+
+static int wtgo_file_handle_read(WT_FILE_HANDLE *file_handle, WT_SESSION *session, wt_off_t offset, size_t len, void *buf) {
+	return wtgoFileHandleRead(((wtgo_file_handle *)file_handle)->handle, offset, len, buf);
+}
+
+static int wtgo_file_handle_size(WT_FILE_HANDLE *file_handle, WT_SESSION *session, wt_off_t *sizep) {
+	return wtgoFileHandleSize(((wtgo_file_handle *)file_handle)->handle, sizep);
+}
+
+static int wtgo_file_handle_sync(WT_FILE_HANDLE *file_handle, WT_SESSION *session) {
+	return wtgoFileHandleSync(((wtgo_file_handle *)file_handle)->handle);
+}
+
+static int wtgo_file_handle_truncate(WT_FILE_HANDLE *file_handle, WT_SESSION *session, wt_off_t offset) {
+	return wtgoFileHandleTruncate(((wtgo_file_handle *)file_handle)->handle, offset);
+}
+
+static int wtgo_file_handle_write(WT_FILE_HANDLE *file_handle, WT_SESSION *session, wt_off_t offset, size_t len, const void *buf) {
+	return wtgoFileHandleWrite(((wtgo_file_handle *)file_handle)->handle, offset, len, (void *)buf);
+}
+
+static int wtgo_file_system_directory_list(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *directory, const char *prefix, char ***dirlistp, uint32_t *countp) {
+	return wtgoFileSystemDirectoryList(((wtgo_file_system *)file_system)->handle, (char *)directory, (char *)prefix, 0, dirlistp, countp);
+}
+
+static int wtgo_file_system_directory_list_single(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *directory, const char *prefix, char ***dirlistp, uint32_t *countp) {
+	return wtgoFileSystemDirectoryList(((wtgo_file_system *)file_system)->handle, (char *)directory, (char *)prefix, 1, dirlistp, countp);
+}
+
+static int wtgo_file_system_directory_list_free(WT_FILE_SYSTEM *file_system, WT_SESSION *session, char **dirlist, uint32_t count) {
+	if (dirlist == NULL) {
+		return 0;
+	}
+
+	for (uint32_t i = 0; i < count; i++) {
+		free(dirlist[i]);
+	}
+
+	free(dirlist);
+
+	return 0;
+}
+
+static int wtgo_file_system_exist(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *name, bool *existp) {
+	return wtgoFileSystemExist(((wtgo_file_system *)file_system)->handle, (char *)name, existp);
+}
+
+static int wtgo_file_system_open_file(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *name, WT_FS_OPEN_FILE_TYPE file_type, uint32_t flags, WT_FILE_HANDLE **file_handlep) {
+	wtgo_file_handle *fh = calloc(1, sizeof(wtgo_file_handle));
+	if (fh == NULL) {
+		return ENOMEM;
+	}
+
+	fh->iface.name = strdup(name);
+	if (fh->iface.name == NULL) {
+		free(fh);
+		return ENOMEM;
+	}
+
+	int ret = wtgoFileSystemOpenFile(((wtgo_file_system *)file_system)->handle, (char *)name, (int)file_type, flags, &fh->handle);
+	if (ret != 0) {
+		free(fh->iface.name);
+		free(fh);
+		return ret;
+	}
+
+	fh->iface.file_system = file_system;
+	fh->iface.close = wtgo_file_handle_close;
+	fh->iface.fh_lock = wtgo_file_handle_lock;
+	fh->iface.fh_read = wtgo_file_handle_read;
+	fh->iface.fh_size = wtgo_file_handle_size;
+	fh->iface.fh_sync = wtgo_file_handle_sync;
+	fh->iface.fh_truncate = wtgo_file_handle_truncate;
+	fh->iface.fh_write = wtgo_file_handle_write;
+
+	*file_handlep = &fh->iface;
+
+	return 0;
+}
+
+static int wtgo_file_system_remove(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *name, uint32_t flags) {
+	return wtgoFileSystemRemove(((wtgo_file_system *)file_system)->handle, (char *)name);
+}
+
+static int wtgo_file_system_rename(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *from, const char *to, uint32_t flags) {
+	return wtgoFileSystemRename(((wtgo_file_system *)file_system)->handle, (char *)from, (char *)to);
+}
+
+static int wtgo_file_system_size(WT_FILE_SYSTEM *file_system, WT_SESSION *session, const char *name, wt_off_t *sizep) {
+	return wtgoFileSystemSize(((wtgo_file_system *)file_system)->handle, (char *)name, sizep);
+}
+
+static int wtgo_file_system_terminate(WT_FILE_SYSTEM *file_system, WT_SESSION *session) {
+	wtgoFileSystemTerminate(((wtgo_file_system *)file_system)->handle);
+	free(file_system);
+
+	return 0;
+}
+
+WT_FILE_SYSTEM *wtgo_file_system_new(uintptr_t handle) {
+	wtgo_file_system *fs = calloc(1, sizeof(wtgo_file_system));
+	if (fs == NULL) {
+		return NULL;
+	}
+
+	fs->iface.fs_directory_list = wtgo_file_system_directory_list;
+	fs->iface.fs_directory_list_single = wtgo_file_system_directory_list_single;
+	fs->iface.fs_directory_list_free = wtgo_file_system_directory_list_free;
+	fs->iface.fs_exist = wtgo_file_system_exist;
+	fs->iface.fs_open_file = wtgo_file_system_open_file;
+	fs->iface.fs_remove = wtgo_file_system_remove;
+	fs->iface.fs_rename = wtgo_file_system_rename;
+	fs->iface.fs_size = wtgo_file_system_size;
+	fs->iface.terminate = wtgo_file_system_terminate;
+	fs->handle = handle;
+
+	return &fs->iface;
+}
+
+uintptr_t wtgo_file_system_handle(WT_FILE_SYSTEM *file_system) {
+	return ((wtgo_file_system *)file_system)->handle;
+}
+
+int wiredtiger_connection_set_file_system(WT_CONNECTION *connection, uintptr_t handle, const char *config) {
+	WT_FILE_SYSTEM *fs = wtgo_file_system_new(handle);
+	if (fs == NULL) {
+		return ENOMEM;
+	}
+
+	int ret = connection->set_file_system(connection, fs, config);
+	if (ret != 0) {
+		free(fs);
+	}
+
+	return ret;
+}
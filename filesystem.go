@@ -0,0 +1,496 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_set_file_system(WT_CONNECTION *connection, uintptr_t handle, const char *config);
+*/
+import (
+	"C"
+)
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"runtime/cgo"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+type FileType int
+
+const (
+	FileTypeCheckpoint FileType = C.WT_FS_OPEN_FILE_TYPE_CHECKPOINT
+	FileTypeData       FileType = C.WT_FS_OPEN_FILE_TYPE_DATA
+	FileTypeDirectory  FileType = C.WT_FS_OPEN_FILE_TYPE_DIRECTORY
+	FileTypeLog        FileType = C.WT_FS_OPEN_FILE_TYPE_LOG
+	FileTypeRegular    FileType = C.WT_FS_OPEN_FILE_TYPE_REGULAR
+)
+
+type FileOpenFlags uint32
+
+const (
+	FileOpenAccessRandom     FileOpenFlags = C.WT_FS_OPEN_ACCESS_RAND
+	FileOpenAccessSequential FileOpenFlags = C.WT_FS_OPEN_ACCESS_SEQ
+	FileOpenCreate           FileOpenFlags = C.WT_FS_OPEN_CREATE
+	FileOpenDirectIO         FileOpenFlags = C.WT_FS_OPEN_DIRECTIO
+	FileOpenDurable          FileOpenFlags = C.WT_FS_OPEN_DURABLE
+	FileOpenExclusive        FileOpenFlags = C.WT_FS_OPEN_EXCLUSIVE
+	FileOpenFixed            FileOpenFlags = C.WT_FS_OPEN_FIXED
+	FileOpenForceMmap        FileOpenFlags = C.WT_FS_OPEN_FORCE_MMAP
+	FileOpenReadOnly         FileOpenFlags = C.WT_FS_OPEN_READONLY
+)
+
+// FileSystem stores WiredTiger's files. Names are paths that already
+// include the database home. Its methods and those of the files it opens are
+// called from WiredTiger threads and must be safe for concurrent use. Errors
+// wrapping fs.ErrNotExist, fs.ErrExist and fs.ErrPermission, or a
+// syscall.Errno, are reported to WiredTiger as the matching errno.
+type FileSystem interface {
+	// List returns the names, without the directory, of the files in dir
+	// that start with prefix.
+	List(dir, prefix string) ([]string, error)
+	Exists(name string) (bool, error)
+	Open(name string, typ FileType, flags FileOpenFlags) (File, error)
+	Remove(name string) error
+	Rename(from, to string) error
+	Size(name string) (int64, error)
+}
+
+type File interface {
+	io.ReaderAt
+	io.WriterAt
+	Size() (int64, error)
+	Truncate(size int64) error
+	Sync() error
+	// Lock takes or releases an advisory lock on the whole file.
+	Lock(lock bool) error
+	Close() error
+}
+
+func (conn *Connection) setFileSystem(f FileSystem) error {
+	h := cgo.NewHandle(f)
+
+	if code := int(C.wiredtiger_connection_set_file_system(conn.wtc, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+func fileSystem(handle C.uintptr_t) FileSystem {
+	return cgo.Handle(handle).Value().(FileSystem)
+}
+
+//export wtgoFileSystemDirectoryList
+func wtgoFileSystemDirectoryList(handle C.uintptr_t, dir, prefix *C.char, single C.int, dirlist ***C.char, count *C.uint32_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		names, err := fileSystem(handle).List(C.GoString(dir), C.GoString(prefix))
+		if err != nil {
+			return err
+		}
+
+		if single != 0 && len(names) > 1 {
+			names = names[:1]
+		}
+
+		*dirlist = nil
+		*count = 0
+
+		if len(names) == 0 {
+			return nil
+		}
+
+		list := (**C.char)(C.malloc(C.size_t(len(names)) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))
+		if list == nil {
+			return fmt.Errorf("allocate directory list: %w", syscall.ENOMEM)
+		}
+
+		entries := unsafe.Slice(list, len(names))
+		for i, name := range names {
+			entries[i] = C.CString(name)
+		}
+
+		*dirlist = list
+		*count = C.uint32_t(len(names))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemExist
+func wtgoFileSystemExist(handle C.uintptr_t, name *C.char, exist *C.bool) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		ok, err := fileSystem(handle).Exists(C.GoString(name))
+		if err != nil {
+			return err
+		}
+
+		*exist = C.bool(ok)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemOpenFile
+func wtgoFileSystemOpenFile(handle C.uintptr_t, name *C.char, typ C.int, flags C.uint32_t, fileHandle *C.uintptr_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		f, err := fileSystem(handle).Open(C.GoString(name), FileType(typ), FileOpenFlags(flags))
+		if err != nil {
+			return err
+		}
+
+		*fileHandle = C.uintptr_t(cgo.NewHandle(f))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemRemove
+func wtgoFileSystemRemove(handle C.uintptr_t, name *C.char) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return fileSystem(handle).Remove(C.GoString(name))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemRename
+func wtgoFileSystemRename(handle C.uintptr_t, from, to *C.char) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return fileSystem(handle).Rename(C.GoString(from), C.GoString(to))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemSize
+func wtgoFileSystemSize(handle C.uintptr_t, name *C.char, size *C.wt_off_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		n, err := fileSystem(handle).Size(C.GoString(name))
+		if err != nil {
+			return err
+		}
+
+		*size = C.wt_off_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileSystemTerminate
+func wtgoFileSystemTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+func fileHandle(handle C.uintptr_t) File {
+	return cgo.Handle(handle).Value().(File)
+}
+
+//export wtgoFileHandleClose
+func wtgoFileHandleClose(handle C.uintptr_t) C.int {
+	h := cgo.Handle(handle)
+	defer h.Delete()
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return h.Value().(File).Close()
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleLock
+func wtgoFileHandleLock(handle C.uintptr_t, lock C.bool) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return fileHandle(handle).Lock(bool(lock))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleRead
+func wtgoFileHandleRead(handle C.uintptr_t, offset C.wt_off_t, length C.size_t, buf unsafe.Pointer) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		p := unsafe.Slice((*byte)(buf), int(length))
+
+		n, err := fileHandle(handle).ReadAt(p, int64(offset))
+		if n == len(p) {
+			return nil
+		}
+
+		if err == nil || errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return fmt.Errorf("read %d bytes at %d: %w", len(p), offset, err)
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleSize
+func wtgoFileHandleSize(handle C.uintptr_t, size *C.wt_off_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		n, err := fileHandle(handle).Size()
+		if err != nil {
+			return err
+		}
+
+		*size = C.wt_off_t(n)
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleSync
+func wtgoFileHandleSync(handle C.uintptr_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return fileHandle(handle).Sync()
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleTruncate
+func wtgoFileHandleTruncate(handle C.uintptr_t, offset C.wt_off_t) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		return fileHandle(handle).Truncate(int64(offset))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoFileHandleWrite
+func wtgoFileHandleWrite(handle C.uintptr_t, offset C.wt_off_t, length C.size_t, buf unsafe.Pointer) C.int {
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		_, err = fileHandle(handle).WriteAt(unsafe.Slice((*byte)(buf), int(length)), int64(offset))
+
+		return err
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+// MemFileSystem is a FileSystem that keeps every file in memory. Its
+// contents outlive the connections opened on it, so a database can be
+// closed and reopened within the process.
+type MemFileSystem struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+func NewMemFileSystem() *MemFileSystem {
+	return &MemFileSystem{files: make(map[string]*memFile)}
+}
+
+func (m *MemFileSystem) List(dir, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir = path.Clean(dir)
+
+	var names []string
+
+	for name := range m.files {
+		if path.Dir(name) == dir && strings.HasPrefix(path.Base(name), prefix) {
+			names = append(names, path.Base(name))
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (m *MemFileSystem) Exists(name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.files[path.Clean(name)]
+
+	return ok, nil
+}
+
+func (m *MemFileSystem) Open(name string, typ FileType, flags FileOpenFlags) (File, error) {
+	// Directories are only opened to be synced.
+	if typ == FileTypeDirectory {
+		return &memFile{}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+
+	f, ok := m.files[name]
+
+	switch {
+	case ok && flags&FileOpenExclusive != 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !ok && flags&FileOpenCreate == 0:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case !ok:
+		f = &memFile{}
+		m.files[name] = f
+	}
+
+	return f, nil
+}
+
+func (m *MemFileSystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+
+	delete(m.files, name)
+
+	return nil
+}
+
+func (m *MemFileSystem) Rename(from, to string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	from, to = path.Clean(from), path.Clean(to)
+
+	f, ok := m.files[from]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: from, Err: fs.ErrNotExist}
+	}
+
+	delete(m.files, from)
+	m.files[to] = f
+
+	return nil
+}
+
+func (m *MemFileSystem) Size(name string) (int64, error) {
+	m.mu.Lock()
+	f, ok := m.files[path.Clean(name)]
+	m.mu.Unlock()
+
+	if !ok {
+		return 0, &fs.PathError{Op: "size", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return f.Size()
+}
+
+// memFile is shared by every handle open on the same name.
+type memFile struct {
+	mu   sync.RWMutex
+	data []byte
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func (f *memFile) WriteAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if end := off + int64(len(p)); end > int64(len(f.data)) {
+		n := len(f.data)
+		f.data = slices.Grow(f.data, int(end)-n)[:end]
+		clear(f.data[n:])
+	}
+
+	return copy(f.data[off:], p), nil
+}
+
+func (f *memFile) Size() (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return int64(len(f.data)), nil
+}
+
+func (f *memFile) Truncate(size int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if size <= int64(len(f.data)) {
+		f.data = f.data[:size]
+		return nil
+	}
+
+	n := len(f.data)
+	f.data = slices.Grow(f.data, int(size)-n)[:size]
+	clear(f.data[n:])
+
+	return nil
+}
+
+func (f *memFile) Sync() error {
+	return nil
+}
+
+func (f *memFile) Lock(lock bool) error {
+	return nil
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
@@ -0,0 +1,130 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+)
+
+// faultFileSystem counts writes and refuses to create files whose name
+// contains "faulty".
+type faultFileSystem struct {
+	wtgo.FileSystem
+	writes atomic.Int64
+}
+
+func (f *faultFileSystem) Open(name string, typ wtgo.FileType, flags wtgo.FileOpenFlags) (wtgo.File, error) {
+	if strings.Contains(filepath.Base(name), "faulty") {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+
+	file, err := f.FileSystem.Open(name, typ, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingFile{File: file, writes: &f.writes}, nil
+}
+
+type countingFile struct {
+	wtgo.File
+	writes *atomic.Int64
+}
+
+func (c *countingFile) WriteAt(p []byte, off int64) (int, error) {
+	c.writes.Add(1)
+	return c.File.WriteAt(p, off)
+}
+
+func TestMemFileSystem(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "db")
+
+	memfs := wtgo.NewMemFileSystem()
+	faultfs := &faultFileSystem{FileSystem: memfs}
+
+	conn, err := wtgo.OpenWithOptions(dir, "create", wtgo.OpenOptions{FileSystem: faultfs})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	if err := session.Create("table:mem", "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	cursor, err := session.OpenCursor("table:mem", "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	data := []record{
+		{k: []any{"a"}, v: []any{"1"}},
+		{k: []any{"b"}, v: []any{"2"}},
+		{k: []any{"c"}, v: []any{"3"}},
+	}
+
+	if err := seed(cursor, data); err != nil {
+		t.Fatalf("seed: %s", err)
+	}
+
+	if err := session.Create("table:faulty", "key_format=S,value_format=S"); !errors.Is(err, wtgo.ErrorCode(syscall.EACCES)) {
+		t.Fatalf("create with injected fault returned err '%v', expected permission denied", err)
+	}
+
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close: %s", err)
+	}
+
+	if faultfs.writes.Load() == 0 {
+		t.Fatalf("no writes went through the file system")
+	}
+
+	if _, err := os.Stat(dir); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("database home was created on disk: %v", err)
+	}
+
+	files, err := memfs.List(dir, "mem")
+	if err != nil {
+		t.Fatalf("list files: %s", err)
+	}
+
+	if len(files) != 1 || files[0] != "mem.wt" {
+		t.Fatalf("got files %v, wanted [mem.wt]", files)
+	}
+
+	conn, err = wtgo.OpenWithOptions(dir, "", wtgo.OpenOptions{FileSystem: memfs})
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	session, err = conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session after reopen: %s", err)
+	}
+
+	cursor, err = session.OpenCursor("table:mem", "")
+	if err != nil {
+		t.Fatalf("open cursor after reopen: %s", err)
+	}
+
+	r, err := searchKey[string, string](cursor, "b")
+	if err != nil {
+		t.Fatalf("search after reopen: %s", err)
+	}
+
+	if r.Value != "2" {
+		t.Fatalf("got value %s after reopen, wanted 2", r.Value)
+	}
+}
@@ -0,0 +1,250 @@
+package wtgo
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo/internal/wtconfig"
+	"github.com/dylrich/wtgo/internal/wtformat"
+	"strings"
+)
+
+type IndexInfo struct {
+	Name      string
+	Columns   []string
+	KeyFormat string
+}
+
+type ColumnGroupInfo struct {
+	Name    string
+	Columns []string
+}
+
+func (s *Session) CreateIndex(table, name string, columns []string, config string) error {
+	return s.createSchemaObject("index", table, name, columns, config)
+}
+
+func (s *Session) DropIndex(table, name string) error {
+	uri := "index:" + tableName(table) + ":" + name
+
+	if err := s.Drop(uri, ""); err != nil {
+		return fmt.Errorf("drop %s: %w", uri, err)
+	}
+
+	return nil
+}
+
+func (s *Session) ListIndices(table string) ([]IndexInfo, error) {
+	entries, err := s.schemaObjects("index", table)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]IndexInfo, 0, len(entries))
+
+	for _, e := range entries {
+		keyFormat, _, err := wtconfig.Get(e.config, "key_format")
+		if err != nil {
+			return nil, fmt.Errorf("parse metadata of index %s: %w", e.name, err)
+		}
+
+		indices = append(indices, IndexInfo{Name: e.name, Columns: e.columns, KeyFormat: keyFormat})
+	}
+
+	return indices, nil
+}
+
+// CreateColumnGroup adds a column group to a table that was created with
+// its name listed in colgroups=(...). Every column group must be created
+// before the table is used.
+func (s *Session) CreateColumnGroup(table, name string, columns []string, config string) error {
+	return s.createSchemaObject("colgroup", table, name, columns, config)
+}
+
+func (s *Session) DropColumnGroup(table, name string) error {
+	uri := "colgroup:" + tableName(table) + ":" + name
+
+	if err := s.Drop(uri, ""); err != nil {
+		return fmt.Errorf("drop %s: %w", uri, err)
+	}
+
+	return nil
+}
+
+func (s *Session) ListColumnGroups(table string) ([]ColumnGroupInfo, error) {
+	entries, err := s.schemaObjects("colgroup", table)
+	if err != nil {
+		return nil, err
+	}
+
+	colgroups := make([]ColumnGroupInfo, 0, len(entries))
+
+	for _, e := range entries {
+		colgroups = append(colgroups, ColumnGroupInfo{Name: e.name, Columns: e.columns})
+	}
+
+	return colgroups, nil
+}
+
+// OpenIndexCursor opens a cursor on an index of table. Values are the
+// projected columns, or every value column of the table when projection is
+// empty. The primary key of the current row is read with GetPrimaryKey.
+func (s *Session) OpenIndexCursor(table, index string, projection []string, config string) (*Cursor, error) {
+	keyColumns, valueColumns, err := s.tableColumns(table)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projection) == 0 {
+		projection = valueColumns
+	}
+
+	columns := make([]string, 0, len(projection)+len(keyColumns))
+	columns = append(columns, projection...)
+	columns = append(columns, keyColumns...)
+
+	uri := "index:" + tableName(table) + ":" + index + "(" + strings.Join(columns, ",") + ")"
+
+	cursor, err := s.OpenCursor(uri, config)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cursor.valuePackers) - len(keyColumns)
+	if n < 0 {
+		cursor.Close()
+		return nil, fmt.Errorf("projection of %s does not include the primary key", uri)
+	}
+
+	cursor.primaryKeyPackers = cursor.valuePackers[n:]
+	cursor.valuePackers = cursor.valuePackers[:n]
+
+	return cursor, nil
+}
+
+func (c *Cursor) GetPrimaryKey(keys ...any) error {
+	if c.primaryKeyPackers == nil {
+		return fmt.Errorf("%s was not opened with OpenIndexCursor", c.URI())
+	}
+
+	if len(keys) != len(c.primaryKeyPackers) {
+		return fmt.Errorf("number of keys does not match primary key format")
+	}
+
+	data, err := c.PackedValue()
+	if err != nil {
+		return err
+	}
+
+	for i, p := range c.valuePackers {
+		var v any
+
+		d, err := p.UnpackField(data, &v)
+		if err != nil {
+			return fmt.Errorf("skip field %d: %w", i, err)
+		}
+
+		data = d
+	}
+
+	for i, p := range c.primaryKeyPackers {
+		d, err := p.UnpackField(data, keys[i])
+		if err != nil {
+			return fmt.Errorf("unpack field: %w", err)
+		}
+
+		data = d
+	}
+
+	return nil
+}
+
+func (s *Session) tableColumns(table string) ([]string, []string, error) {
+	uri := "table:" + tableName(table)
+
+	config, err := s.Metadata(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyFormat, _, err := wtconfig.Get(config, "key_format")
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse metadata of %s: %w", uri, err)
+	}
+
+	keyPackers, err := wtformat.ParseFormat(keyFormat)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse key format: %w", err)
+	}
+
+	value, _, err := wtconfig.Get(config, "columns")
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse metadata of %s: %w", uri, err)
+	}
+
+	columns, err := wtconfig.List(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse columns of %s: %w", uri, err)
+	}
+
+	if len(columns) <= len(keyPackers) {
+		return nil, nil, fmt.Errorf("%s has no column names", uri)
+	}
+
+	return columns[:len(keyPackers)], columns[len(keyPackers):], nil
+}
+
+func (s *Session) createSchemaObject(kind, table, name string, columns []string, config string) error {
+	uri := kind + ":" + tableName(table) + ":" + name
+
+	c := "columns=(" + strings.Join(columns, ",") + ")"
+	if config != "" {
+		c += "," + config
+	}
+
+	if err := s.Create(uri, c); err != nil {
+		return fmt.Errorf("create %s: %w", uri, err)
+	}
+
+	return nil
+}
+
+type schemaObject struct {
+	name    string
+	columns []string
+	config  string
+}
+
+func (s *Session) schemaObjects(kind, table string) ([]schemaObject, error) {
+	prefix := kind + ":" + tableName(table) + ":"
+
+	keys, err := s.metadataKeys(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]schemaObject, 0, len(keys))
+
+	for _, key := range keys {
+		config, err := s.Metadata(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, _, err := wtconfig.Get(config, "columns")
+		if err != nil {
+			return nil, fmt.Errorf("parse metadata of %s: %w", key, err)
+		}
+
+		columns, err := wtconfig.List(value)
+		if err != nil {
+			return nil, fmt.Errorf("parse columns of %s: %w", key, err)
+		}
+
+		objects = append(objects, schemaObject{name: strings.TrimPrefix(key, prefix), columns: columns, config: config})
+	}
+
+	return objects, nil
+}
+
+func tableName(table string) string {
+	return strings.TrimPrefix(table, "table:")
+}
@@ -0,0 +1,227 @@
+package wtgo_test
+
+import (
+	"github.com/dylrich/wtgo"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIndices(t *testing.T) {
+	tablename := "table:people"
+	tableconf := "key_format=Q,value_format=SSSQ,columns=(id,first,last,email,age)"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := env.session.CreateIndex(tablename, "name", []string{"last", "first"}, ""); err != nil {
+		t.Fatalf("create composite index: %s", err)
+	}
+
+	// WiredTiger has no unique indices, so email uniqueness is maintained
+	// by the application and the index is expected to hold one row per key.
+	if err := env.session.CreateIndex(tablename, "email", []string{"email"}, ""); err != nil {
+		t.Fatalf("create email index: %s", err)
+	}
+
+	records := []record{
+		{k: []any{uint64(1)}, v: []any{"ada", "lovelace", "ada@example.com", uint64(36)}},
+		{k: []any{uint64(2)}, v: []any{"alan", "turing", "alan@example.com", uint64(41)}},
+		{k: []any{uint64(3)}, v: []any{"grace", "hopper", "grace@example.com", uint64(85)}},
+		{k: []any{uint64(4)}, v: []any{"charles", "babbage", "charles@example.com", uint64(79)}},
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	indices, err := env.session.ListIndices(tablename)
+	if err != nil {
+		t.Fatalf("list indices: %s", err)
+	}
+
+	names := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		names = append(names, idx.Name)
+	}
+
+	if diff := cmp.Diff([]string{"email", "name"}, names); diff != "" {
+		t.Fatalf("indices don't match (-want +got):\n%s", diff)
+	}
+
+	t.Run("composite", func(t *testing.T) {
+		cursor, err := env.session.OpenIndexCursor(tablename, "name", []string{"age"}, "")
+		if err != nil {
+			t.Fatalf("open index cursor: %s", err)
+		}
+
+		t.Cleanup(func() { cursor.Close() })
+
+		type row struct {
+			Last, First string
+			Age, ID     uint64
+		}
+
+		got := make([]row, 0, len(records))
+
+		for cursor.Next() {
+			var r row
+
+			if err := cursor.GetKey(&r.Last, &r.First); err != nil {
+				t.Fatalf("get key: %s", err)
+			}
+
+			if err := cursor.GetValue(&r.Age); err != nil {
+				t.Fatalf("get value: %s", err)
+			}
+
+			if err := cursor.GetPrimaryKey(&r.ID); err != nil {
+				t.Fatalf("get primary key: %s", err)
+			}
+
+			got = append(got, r)
+		}
+
+		if err := cursor.Err(); err != nil {
+			t.Fatalf("iteration: %s", err)
+		}
+
+		want := []row{
+			{Last: "babbage", First: "charles", Age: 79, ID: 4},
+			{Last: "hopper", First: "grace", Age: 85, ID: 3},
+			{Last: "lovelace", First: "ada", Age: 36, ID: 1},
+			{Last: "turing", First: "alan", Age: 41, ID: 2},
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Fatalf("index rows don't match (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("unique-by-convention", func(t *testing.T) {
+		cursor, err := env.session.OpenIndexCursor(tablename, "email", nil, "")
+		if err != nil {
+			t.Fatalf("open index cursor: %s", err)
+		}
+
+		t.Cleanup(func() { cursor.Close() })
+
+		if err := cursor.SetKey("grace@example.com"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		var first, last, email string
+		var age, id uint64
+
+		if err := cursor.GetValue(&first, &last, &email, &age); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		if err := cursor.GetPrimaryKey(&id); err != nil {
+			t.Fatalf("get primary key: %s", err)
+		}
+
+		if diff := cmp.Diff([]any{"grace", "hopper", uint64(85), uint64(3)}, []any{first, last, age, id}); diff != "" {
+			t.Fatalf("row doesn't match (-want +got):\n%s", diff)
+		}
+
+		if cursor.Next() {
+			var next string
+
+			if err := cursor.GetKey(&next); err != nil {
+				t.Fatalf("get next key: %s", err)
+			}
+
+			if next == email {
+				t.Fatalf("found a second row for %s", email)
+			}
+		}
+	})
+
+	if err := env.session.DropIndex(tablename, "email"); err != nil {
+		t.Fatalf("drop index: %s", err)
+	}
+
+	if _, err := env.session.OpenIndexCursor(tablename, "email", nil, ""); err == nil {
+		t.Fatalf("expected opening a dropped index to fail")
+	}
+}
+
+func TestColumnGroups(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	tablename := "table:events"
+	tableconf := "key_format=Q,value_format=SSQ,columns=(id,kind,payload,size),colgroups=(meta,body)"
+
+	if err := env.session.Create(tablename, tableconf); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	if err := env.session.CreateColumnGroup(tablename, "meta", []string{"kind", "size"}, ""); err != nil {
+		t.Fatalf("create meta column group: %s", err)
+	}
+
+	if err := env.session.CreateColumnGroup(tablename, "body", []string{"payload"}, ""); err != nil {
+		t.Fatalf("create body column group: %s", err)
+	}
+
+	colgroups, err := env.session.ListColumnGroups(tablename)
+	if err != nil {
+		t.Fatalf("list column groups: %s", err)
+	}
+
+	want := []wtgo.ColumnGroupInfo{
+		{Name: "body", Columns: []string{"payload"}},
+		{Name: "meta", Columns: []string{"kind", "size"}},
+	}
+
+	if diff := cmp.Diff(want, colgroups); diff != "" {
+		t.Fatalf("column groups don't match (-want +got):\n%s", diff)
+	}
+
+	cursor, err := env.session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	if err := seed(cursor, []record{{k: []any{uint64(1)}, v: []any{"click", "{}", uint64(2)}}}); err != nil {
+		t.Fatalf("seed: %s", err)
+	}
+
+	meta, err := env.session.OpenCursor("colgroup:events:meta", "")
+	if err != nil {
+		t.Fatalf("open column group cursor: %s", err)
+	}
+
+	if err := meta.SetKey(uint64(1)); err != nil {
+		t.Fatalf("set key: %s", err)
+	}
+
+	if err := meta.Search(); err != nil {
+		t.Fatalf("search: %s", err)
+	}
+
+	var kind string
+	var size uint64
+
+	if err := meta.GetValue(&kind, &size); err != nil {
+		t.Fatalf("get value: %s", err)
+	}
+
+	if diff := cmp.Diff([]any{"click", uint64(2)}, []any{kind, size}); diff != "" {
+		t.Fatalf("column group row doesn't match (-want +got):\n%s", diff)
+	}
+}
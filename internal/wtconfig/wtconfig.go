@@ -0,0 +1,241 @@
+package wtconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Pair struct {
+	Key   string
+	Value string
+}
+
+func Parse(config string) ([]Pair, error) {
+	config = unwrap(strings.TrimSpace(config))
+
+	items, err := split(config)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]Pair, 0, len(items))
+
+	for _, item := range items {
+		key, value, found := cut(item)
+
+		key = unquote(strings.TrimSpace(key))
+		if key == "" {
+			return nil, fmt.Errorf("empty key in '%s'", item)
+		}
+
+		if !found {
+			value = "true"
+		}
+
+		pairs = append(pairs, Pair{Key: key, Value: unquote(strings.TrimSpace(value))})
+	}
+
+	return pairs, nil
+}
+
+func Get(config, key string) (string, bool, error) {
+	pairs, err := Parse(config)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, p := range pairs {
+		if p.Key == key {
+			return p.Value, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func List(value string) ([]string, error) {
+	value = unwrap(strings.TrimSpace(value))
+
+	items, err := split(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		items[i] = unquote(strings.TrimSpace(item))
+	}
+
+	return items, nil
+}
+
+// AppendList appends item to the list value of key in config. Items
+// already in the list keep their raw text, so item must be quoted where
+// needed. WiredTiger uses the last value of a repeated key, so the combined
+// list is appended rather than spliced into place.
+func AppendList(config, key, item string) (string, error) {
+	pairs, err := Parse(config)
+	if err != nil {
+		return "", err
+	}
+
+	var items []string
+
+	for _, p := range pairs {
+		if p.Key != key {
+			continue
+		}
+
+		items, err = split(unwrap(strings.TrimSpace(p.Value)))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	items = append(items, item)
+
+	c := key + "=[" + strings.Join(items, ",") + "]"
+	if config != "" {
+		c = config + "," + c
+	}
+
+	return c, nil
+}
+
+// Quote returns s as a config value, quoting and escaping it when it is
+// empty or holds characters that would otherwise be parsed.
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, ",=:()[]{}\"\\ \t\r\n") {
+		return s
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+func split(s string) ([]string, error) {
+	items := make([]string, 0, 4)
+
+	var depth int
+	var quoted bool
+	var start int
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+			if c == '\\' {
+				i++
+			}
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in '%s'", s)
+			}
+		case c == ',' && depth == 0:
+			if item := strings.TrimSpace(s[start:i]); item != "" {
+				items = append(items, item)
+			}
+
+			start = i + 1
+		}
+	}
+
+	if depth != 0 || quoted {
+		return nil, fmt.Errorf("unterminated value in '%s'", s)
+	}
+
+	if item := strings.TrimSpace(s[start:]); item != "" {
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func cut(item string) (string, string, bool) {
+	var quoted bool
+
+	for i := 0; i < len(item); i++ {
+		switch c := item[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+			if c == '\\' {
+				i++
+			}
+		case c == '=' || c == ':':
+			return item[:i], item[i+1:], true
+		case c == '(' || c == '[':
+			return item, "", false
+		}
+	}
+
+	return item, "", false
+}
+
+func unwrap(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+
+	if (s[0] != '(' || s[len(s)-1] != ')') && (s[0] != '[' || s[len(s)-1] != ']') {
+		return s
+	}
+
+	var depth int
+
+	for i := 0; i < len(s)-1; i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		}
+
+		if depth == 0 {
+			return s
+		}
+	}
+
+	return s[1 : len(s)-1]
+}
+
+// unquote strips the quotes around s and undoes the escaping done by
+// Quote.
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,216 @@
+package wtconfig_test
+
+import (
+	"github.com/dylrich/wtgo/internal/wtconfig"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		config string
+		want   []wtconfig.Pair
+		err    bool
+	}{
+		"empty": {
+			config: "",
+			want:   []wtconfig.Pair{},
+		},
+		"simple": {
+			config: "key_format=S,value_format=SQQS",
+			want: []wtconfig.Pair{
+				{Key: "key_format", Value: "S"},
+				{Key: "value_format", Value: "SQQS"},
+			},
+		},
+		"nested": {
+			config: "columns=(id,name,hits),checkpoint=(nightly=3,WiredTigerCheckpoint=4)",
+			want: []wtconfig.Pair{
+				{Key: "columns", Value: "(id,name,hits)"},
+				{Key: "checkpoint", Value: "(nightly=3,WiredTigerCheckpoint=4)"},
+			},
+		},
+		"wrapped": {
+			config: "(addr=\"01c0\",order=3)",
+			want: []wtconfig.Pair{
+				{Key: "addr", Value: "01c0"},
+				{Key: "order", Value: "3"},
+			},
+		},
+		"bare-key": {
+			config: "create,cache_size=1GB",
+			want: []wtconfig.Pair{
+				{Key: "create", Value: "true"},
+				{Key: "cache_size", Value: "1GB"},
+			},
+		},
+		"quoted-separators": {
+			config: "app_metadata=\"a=b,c\",type=file",
+			want: []wtconfig.Pair{
+				{Key: "app_metadata", Value: "a=b,c"},
+				{Key: "type", Value: "file"},
+			},
+		},
+		"unbalanced": {
+			config: "columns=(id,name",
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pairs, err := wtconfig.Parse(tc.config)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %v", pairs)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, pairs); diff != "" {
+				t.Fatalf("pairs don't match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	config := "columns=(id,name,hits),key_format=S"
+
+	v, ok, err := wtconfig.Get(config, "columns")
+	if err != nil {
+		t.Fatalf("get: %s", err)
+	}
+
+	if !ok {
+		t.Fatalf("columns not found")
+	}
+
+	if diff := cmp.Diff("(id,name,hits)", v); diff != "" {
+		t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+	}
+
+	if _, ok, _ := wtconfig.Get(config, "value_format"); ok {
+		t.Fatalf("found missing key value_format")
+	}
+}
+
+func TestList(t *testing.T) {
+	cases := map[string]struct {
+		value string
+		want  []string
+	}{
+		"empty": {
+			value: "()",
+			want:  []string{},
+		},
+		"columns": {
+			value: "(id,name,hits)",
+			want:  []string{"id", "name", "hits"},
+		},
+		"nested": {
+			value: "(a=(x,y),b)",
+			want:  []string{"a=(x,y)", "b"},
+		},
+		"unwrapped": {
+			value: "id",
+			want:  []string{"id"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			items, err := wtconfig.List(tc.value)
+			if err != nil {
+				t.Fatalf("list: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, items); diff != "" {
+				t.Fatalf("items don't match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppendList(t *testing.T) {
+	cases := map[string]struct {
+		config string
+		item   string
+		want   string
+	}{
+		"empty": {
+			config: "",
+			item:   "/lib/a.so",
+			want:   "extensions=[/lib/a.so]",
+		},
+		"no-list": {
+			config: "create",
+			item:   "/lib/a.so",
+			want:   "create,extensions=[/lib/a.so]",
+		},
+		"existing": {
+			config: "create,extensions=[\"/opt/my libs/a,b=(c).so\",/lib/b.so=(entry=init,config=(x=\"1,2\"))]",
+			item:   wtconfig.Quote("/lib/c d.so") + "=(early_load=true)",
+			want:   "create,extensions=[\"/opt/my libs/a,b=(c).so\",/lib/b.so=(entry=init,config=(x=\"1,2\"))],extensions=[\"/opt/my libs/a,b=(c).so\",/lib/b.so=(entry=init,config=(x=\"1,2\")),\"/lib/c d.so\"=(early_load=true)]",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config, err := wtconfig.AppendList(tc.config, "extensions", tc.item)
+			if err != nil {
+				t.Fatalf("append list: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, config); diff != "" {
+				t.Fatalf("config doesn't match (-want +got):\n%s", diff)
+			}
+
+			pairs, err := wtconfig.Parse(config)
+			if err != nil {
+				t.Fatalf("parse config: %s", err)
+			}
+
+			items, err := wtconfig.List(pairs[len(pairs)-1].Value)
+			if err != nil {
+				t.Fatalf("list extensions: %s", err)
+			}
+
+			if got := items[len(items)-1]; got != tc.item {
+				t.Fatalf("last extension is '%s', expected '%s'", got, tc.item)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	cases := map[string]string{
+		"":                  `""`,
+		"/lib/a.so":         "/lib/a.so",
+		"/opt/my libs/a.so": `"/opt/my libs/a.so"`,
+		"a,b=(c)":           `"a,b=(c)"`,
+		`say "hi" \ bye`:    `"say \"hi\" \\ bye"`,
+	}
+
+	for s, want := range cases {
+		quoted := wtconfig.Quote(s)
+		if quoted != want {
+			t.Fatalf("Quote(%q) is %s, expected %s", s, quoted, want)
+		}
+
+		pairs, err := wtconfig.Parse("k=" + quoted + ",next=1")
+		if err != nil {
+			t.Fatalf("parse quoted %q: %s", s, err)
+		}
+
+		if diff := cmp.Diff([]wtconfig.Pair{{Key: "k", Value: s}, {Key: "next", Value: "1"}}, pairs); diff != "" {
+			t.Fatalf("quoted %q doesn't parse back (-want +got):\n%s", s, diff)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package wtintpack
 
 import (
+	"errors"
 	"math"
 )
 
+var (
+	ErrShortBuffer   = errors.New("packed integer is truncated")
+	ErrInvalidMarker = errors.New("invalid packed integer marker")
+)
+
 const (
 	negMultiMarker byte = 0x10
 	neg2ByteMarker byte = 0x20
@@ -191,3 +197,43 @@ func UnpackInt(buf []byte) ([]byte, int64) {
 		return b, int64(x)
 	}
 }
+
+// Size returns the length of the packed integer at the start of buf. It
+// checks the input UnpackInt and UnpackUint assume to be well-formed.
+func Size(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, ErrShortBuffer
+	}
+
+	var n int
+
+	switch buf[0] & 0xf0 {
+	case negMultiMarker:
+		lz := int(buf[0] & 0xf)
+		if lz > 8 {
+			return 0, ErrInvalidMarker
+		}
+
+		n = 1 + 8 - lz
+	case neg2ByteMarker, neg2ByteMarker | 0x10, pos2ByteMarker, pos2ByteMarker | 0x10:
+		n = 2
+	case neg1ByteMarker, neg1ByteMarker | 0x10, neg1ByteMarker | 0x20, neg1ByteMarker | 0x30,
+		pos1ByteMarker, pos1ByteMarker | 0x10, pos1ByteMarker | 0x20, pos1ByteMarker | 0x30:
+		n = 1
+	case posMultiMarker:
+		length := int(buf[0] & 0xf)
+		if length > 8 {
+			return 0, ErrInvalidMarker
+		}
+
+		n = 1 + length
+	default:
+		return 0, ErrInvalidMarker
+	}
+
+	if len(buf) < n {
+		return 0, ErrShortBuffer
+	}
+
+	return n, nil
+}
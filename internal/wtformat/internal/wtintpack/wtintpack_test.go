@@ -1,6 +1,7 @@
 package wtintpack
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -185,3 +186,34 @@ func TestPackInt(t *testing.T) {
 		}
 	})
 }
+
+func TestSize(t *testing.T) {
+	for _, n := range []int64{math.MinInt64, -8257, -8256, -65, -64, -1, 0, 63, 64, 8255, 8256, 8257, math.MaxInt64} {
+		buf := PackInt(nil, n)
+
+		size, err := Size(buf)
+		if err != nil {
+			t.Fatalf("Size(PackInt(%d)): %s", n, err)
+		}
+
+		if size != len(buf) {
+			t.Fatalf("Size(PackInt(%d)) is %d, expected %d", n, size, len(buf))
+		}
+
+		if _, err := Size(buf[:len(buf)-1]); !errors.Is(err, ErrShortBuffer) {
+			t.Fatalf("Size of truncated PackInt(%d) returned err '%v', expected short buffer", n, err)
+		}
+	}
+
+	buf := PackUint(nil, math.MaxUint64)
+
+	if size, err := Size(buf); err != nil || size != len(buf) {
+		t.Fatalf("Size(PackUint(MaxUint64)) returned %d, %v, expected %d", size, err, len(buf))
+	}
+
+	for _, b := range [][]byte{{0x00}, {0xf0}, {0x1f}, {0xe9}} {
+		if _, err := Size(b); !errors.Is(err, ErrInvalidMarker) {
+			t.Fatalf("Size(%v) returned err '%v', expected invalid marker", b, err)
+		}
+	}
+}
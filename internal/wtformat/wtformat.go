@@ -13,6 +13,18 @@ type FieldPacker interface {
 }
 
 
+// unpackInt checks that buf starts with a whole packed integer before
+// decoding it.
+func unpackInt(buf []byte) ([]byte, int64, error) {
+	if _, err := wtintpack.Size(buf); err != nil {
+		return nil, 0, fmt.Errorf("malformed field: %w", err)
+	}
+
+	buf, x := wtintpack.UnpackInt(buf)
+
+	return buf, x, nil
+}
+
 type fieldPackerInt8 struct {
 }
 
@@ -30,11 +42,19 @@ func (p fieldPackerInt8) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerInt8) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *int8:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int8(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int8(x)
 		return buf, nil
 	default:
@@ -59,11 +79,19 @@ func (p fieldPackerUint8) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerUint8) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *uint8:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint8(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint8(x)
 		return buf, nil
 	default:
@@ -88,11 +116,19 @@ func (p fieldPackerInt16) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerInt16) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *int16:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int16(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int16(x)
 		return buf, nil
 	default:
@@ -117,11 +153,19 @@ func (p fieldPackerUint16) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerUint16) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *uint16:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint16(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint16(x)
 		return buf, nil
 	default:
@@ -146,11 +190,19 @@ func (p fieldPackerInt32) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerInt32) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *int32:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int32(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int32(x)
 		return buf, nil
 	default:
@@ -175,11 +227,19 @@ func (p fieldPackerUint32) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerUint32) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *uint32:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint32(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint32(x)
 		return buf, nil
 	default:
@@ -204,11 +264,19 @@ func (p fieldPackerInt64) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerInt64) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *int64:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int64(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = int64(x)
 		return buf, nil
 	default:
@@ -233,11 +301,19 @@ func (p fieldPackerUint64) PackField(data any, buf []byte) ([]byte, error) {
 func (p fieldPackerUint64) UnpackField(buf []byte, data any) ([]byte, error) {
 	switch v := data.(type) {
 	case *uint64:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint64(x)
 		return buf, nil
 	case *any:
-		buf, x := wtintpack.UnpackInt(buf)
+		buf, x, err := unpackInt(buf)
+		if err != nil {
+			return nil, err
+		}
+
 		*v = uint64(x)
 		return buf, nil
 	default:
@@ -272,6 +348,10 @@ func (p fieldPackerFixedSizeString) PackField(data any, buf []byte) ([]byte, err
 	return buf, nil
 }
 func (p fieldPackerFixedSizeString) UnpackField(buf []byte, data any) ([]byte, error) {
+	if len(buf) < p.size {
+		return nil, fmt.Errorf("malformed field: %d bytes, expected %d", len(buf), p.size)
+	}
+
 	switch v := data.(type) {
 	case *string:
 		*v = string(buf[:p.size])
@@ -294,6 +374,10 @@ func (p fieldPackerNullTerminatedString) UnpackField(buf []byte, data any) ([]by
 	var s string
 
 	if p.size > 0 {
+		if len(buf) < p.size {
+			return nil, fmt.Errorf("malformed field: %d bytes, expected %d", len(buf), p.size)
+		}
+
 		s = string(buf[:p.size])
 		buf = buf[p.size:]
 		if len(buf) > 0 && buf[0] == 0 {
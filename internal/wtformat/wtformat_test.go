@@ -402,3 +402,44 @@ func TestParseFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestUnpackFieldMalformed(t *testing.T) {
+	cases := map[string]struct {
+		format string
+		packed []byte
+		vars   []any
+	}{
+		"int-empty":               {format: "q", packed: []byte{}, vars: []any{int64VarPtr()}},
+		"int-truncated-2-byte":    {format: "q", packed: []byte{0xc0}, vars: []any{int64VarPtr()}},
+		"int-truncated-multi":     {format: "Q", packed: []byte{0xe4, 0x01, 0x02}, vars: []any{uint64VarPtr()}},
+		"int-truncated-negative":  {format: "i", packed: []byte{0x14, 0xff}, vars: []any{int32VarPtr()}},
+		"int-invalid-marker":      {format: "I", packed: []byte{0x00}, vars: []any{uint32VarPtr()}},
+		"int-invalid-length":      {format: "Q", packed: []byte{0xef, 0, 0, 0, 0, 0, 0, 0, 0, 0}, vars: []any{uint64VarPtr()}},
+		"string-fixed-short":      {format: "5s", packed: []byte("abc"), vars: []any{strVarPtr()}},
+		"string-sized-null-short": {format: "5S", packed: []byte("abc"), vars: []any{strVarPtr()}},
+		"string-null-missing":     {format: "S", packed: []byte("abc"), vars: []any{strVarPtr()}},
+		"second-field-truncated":  {format: "Sq", packed: []byte{'a', 0, 0xc1}, vars: []any{strVarPtr(), int64VarPtr()}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			packers, err := wtformat.ParseFormat(tc.format)
+			if err != nil {
+				t.Fatalf("parse format: %s", err)
+			}
+
+			buf := tc.packed
+
+			for i, p := range packers {
+				b, err := p.UnpackField(buf, tc.vars[i])
+				if err != nil {
+					return
+				}
+
+				buf = b
+			}
+
+			t.Fatalf("unpacking %v as '%s' succeeded", tc.packed, tc.format)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_session_join(WT_SESSION *session, WT_CURSOR *join_cursor, WT_CURSOR *ref_cursor, const char *config) {
+	return session->join(session, join_cursor, ref_cursor, config);
+}
+*/
+import (
+	"C"
+)
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+type JoinCompare string
+
+const (
+	JoinCompareEqual              JoinCompare = "eq"
+	JoinCompareGreaterThan        JoinCompare = "gt"
+	JoinCompareGreaterThanOrEqual JoinCompare = "ge"
+	JoinCompareLessThan           JoinCompare = "lt"
+	JoinCompareLessThanOrEqual    JoinCompare = "le"
+)
+
+type JoinCondition struct {
+	// Cursor is an index cursor whose key has been set with SetKey.
+	Cursor  *Cursor
+	Compare JoinCompare
+
+	// Or combines this condition with the previous ones for the same index
+	// using "operation=or" instead of the default "and".
+	Or bool
+
+	Bloom          bool
+	BloomCount     uint64
+	BloomBitCount  uint64
+	BloomHashCount uint64
+}
+
+func (j JoinCondition) config() string {
+	parts := []string{"compare=" + string(j.Compare)}
+
+	if j.Or {
+		parts = append(parts, "operation=or")
+	}
+
+	if j.Bloom {
+		parts = append(parts, "strategy=bloom", fmt.Sprintf("count=%d", j.BloomCount))
+
+		if j.BloomBitCount != 0 {
+			parts = append(parts, fmt.Sprintf("bloom_bit_count=%d", j.BloomBitCount))
+		}
+
+		if j.BloomHashCount != 0 {
+			parts = append(parts, fmt.Sprintf("bloom_hash_count=%d", j.BloomHashCount))
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Join returns a cursor over the rows of the table at uri that satisfy every
+// condition. The returned cursor is iterated with Next and read with GetKey
+// and GetValue like a table cursor.
+func (s *Session) Join(uri string, conditions ...JoinCondition) (*Cursor, error) {
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("join needs at least one condition")
+	}
+
+	join, err := s.OpenCursor("join:"+uri, "")
+	if err != nil {
+		return nil, fmt.Errorf("open join cursor: %w", err)
+	}
+
+	for i, cond := range conditions {
+		if err := s.join(join, cond); err != nil {
+			join.Close()
+			return nil, fmt.Errorf("join condition %d: %w", i, err)
+		}
+	}
+
+	return join, nil
+}
+
+func (s *Session) join(join *Cursor, cond JoinCondition) error {
+	compare, err := cond.Cursor.positionJoin(cond.Compare)
+	if err != nil {
+		return err
+	}
+
+	cond.Compare = compare
+
+	configcstr := C.CString(cond.config())
+	defer C.free(unsafe.Pointer(configcstr))
+
+	if code := int(C.wiredtiger_session_join(s.wtsession, join.wtcursor, cond.Cursor.wtcursor, configcstr)); code != 0 {
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+// positionJoin positions an index cursor at its key as required by
+// WT_SESSION::join. When the key is missing, range comparisons move to the
+// nearest key inside the range and are relaxed to their inclusive forms.
+func (c *Cursor) positionJoin(compare JoinCompare) (JoinCompare, error) {
+	err := c.Search()
+	if err == nil || !errors.Is(err, ErrNotFound) || compare == JoinCompareEqual {
+		return compare, err
+	}
+
+	near, err := c.SearchNear()
+	if err != nil {
+		return compare, err
+	}
+
+	switch compare {
+	case JoinCompareGreaterThan, JoinCompareGreaterThanOrEqual:
+		if near == CursorComparisonLessThan && !c.Next() {
+			return compare, errors.Join(ErrNotFound, c.Err())
+		}
+
+		return JoinCompareGreaterThanOrEqual, nil
+	case JoinCompareLessThan, JoinCompareLessThanOrEqual:
+		if near == CursorComparisonGreaterThan && !c.Prev() {
+			return compare, errors.Join(ErrNotFound, c.Err())
+		}
+
+		return JoinCompareLessThanOrEqual, nil
+	default:
+		return compare, fmt.Errorf("unknown join comparison '%s'", compare)
+	}
+}
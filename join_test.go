@@ -0,0 +1,125 @@
+package wtgo_test
+
+import (
+	"github.com/dylrich/wtgo"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoin(t *testing.T) {
+	tablename := "table:people"
+	tableconf := "key_format=Q,value_format=SQ,columns=(id,name,age)"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := env.session.Create("index:people:name", "columns=(name)"); err != nil {
+		t.Fatalf("create name index: %s", err)
+	}
+
+	if err := env.session.Create("index:people:age", "columns=(age)"); err != nil {
+		t.Fatalf("create age index: %s", err)
+	}
+
+	records := []record{
+		{k: []any{uint64(1)}, v: []any{"alice", uint64(25)}},
+		{k: []any{uint64(2)}, v: []any{"bob", uint64(31)}},
+		{k: []any{uint64(3)}, v: []any{"mallory", uint64(42)}},
+		{k: []any{uint64(4)}, v: []any{"trent", uint64(37)}},
+		{k: []any{uint64(5)}, v: []any{"victor", uint64(58)}},
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	cases := map[string]struct {
+		conditions func(t *testing.T) []wtgo.JoinCondition
+		want       []uint64
+	}{
+		"age-range": {
+			conditions: func(t *testing.T) []wtgo.JoinCondition {
+				return []wtgo.JoinCondition{
+					indexCondition(t, env.session, "index:people:age", uint64(30), wtgo.JoinCompareGreaterThanOrEqual, false),
+					indexCondition(t, env.session, "index:people:age", uint64(50), wtgo.JoinCompareLessThan, false),
+				}
+			},
+			want: []uint64{2, 3, 4},
+		},
+		"age-and-name": {
+			conditions: func(t *testing.T) []wtgo.JoinCondition {
+				return []wtgo.JoinCondition{
+					indexCondition(t, env.session, "index:people:age", uint64(30), wtgo.JoinCompareGreaterThan, false),
+					indexCondition(t, env.session, "index:people:name", "m", wtgo.JoinCompareGreaterThanOrEqual, false),
+				}
+			},
+			want: []uint64{3, 4, 5},
+		},
+		"name-equal": {
+			conditions: func(t *testing.T) []wtgo.JoinCondition {
+				return []wtgo.JoinCondition{
+					indexCondition(t, env.session, "index:people:name", "trent", wtgo.JoinCompareEqual, true),
+				}
+			},
+			want: []uint64{4},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			join, err := env.session.Join(tablename, tc.conditions(t)...)
+			if err != nil {
+				t.Fatalf("join: %s", err)
+			}
+
+			t.Cleanup(func() { join.Close() })
+
+			got := make([]uint64, 0, len(tc.want))
+
+			for join.Next() {
+				var id uint64
+
+				if err := join.GetKey(&id); err != nil {
+					t.Fatalf("get key: %s", err)
+				}
+
+				got = append(got, id)
+			}
+
+			if err := join.Err(); err != nil {
+				t.Fatalf("iteration: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("joined keys don't match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func indexCondition(t *testing.T, session *wtgo.Session, uri string, key any, compare wtgo.JoinCompare, bloom bool) wtgo.JoinCondition {
+	t.Helper()
+
+	cursor, err := session.OpenCursor(uri, "")
+	if err != nil {
+		t.Fatalf("open index cursor %s: %s", uri, err)
+	}
+
+	t.Cleanup(func() { cursor.Close() })
+
+	if err := cursor.SetKey(key); err != nil {
+		t.Fatalf("set index key: %s", err)
+	}
+
+	return wtgo.JoinCondition{
+		Cursor:     cursor,
+		Compare:    compare,
+		Bloom:      bloom,
+		BloomCount: 16,
+	}
+}
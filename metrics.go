@@ -0,0 +1,227 @@
+package wtgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+type MetricsOptions struct {
+	// Tables lists the table or index uris whose statistics are exported
+	// next to the connection's, labelled with uri. Missing uris are skipped.
+	Tables []string
+
+	Mode StatisticsMode
+
+	// Filter, if set, selects the statistics that are exported.
+	Filter func(Statistic) bool
+}
+
+var errMetricsHandlerClosed = errors.New("metrics handler is closed")
+
+// MetricsHandler serves the connection's statistics in the Prometheus text
+// exposition format. Scrapes are serialized on a dedicated session.
+type MetricsHandler struct {
+	conn *Connection
+	opts MetricsOptions
+
+	mu      sync.Mutex
+	session *Session
+	closed  bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// metricsWorker lets the connection close a MetricsHandler through the
+// worker interface.
+type metricsWorker struct {
+	h *MetricsHandler
+}
+
+func (w metricsWorker) Stop() error {
+	return w.h.Close()
+}
+
+func (conn *Connection) NewMetricsHandler(opts MetricsOptions) (*MetricsHandler, error) {
+	session, err := conn.OpenSession("")
+	if err != nil {
+		return nil, fmt.Errorf("open metrics session: %w", err)
+	}
+
+	h := &MetricsHandler{conn: conn, session: session, opts: opts}
+
+	conn.addWorker(metricsWorker{h})
+
+	return h, nil
+}
+
+// Close closes the handler's session. It is called when the connection
+// closes, and scrapes fail afterwards.
+func (h *MetricsHandler) Close() error {
+	h.closeOnce.Do(func() {
+		h.conn.removeWorker(metricsWorker{h})
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.closed = true
+		h.closeErr = h.session.Close("")
+	})
+
+	return h.closeErr
+}
+
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := h.render()
+	if errors.Is(err, errMetricsHandlerClosed) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(body)
+}
+
+type metricSample struct {
+	uri   string
+	value int64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	samples []metricSample
+}
+
+type metricFamilies struct {
+	families []*metricFamily
+	byName   map[string]*metricFamily
+
+	// names remembers the name given to each statistic, so that statistics
+	// whose descriptions sanitize to the same name get the same suffix for
+	// every uri.
+	names map[string]string
+}
+
+func (f *metricFamilies) add(prefix, uri string, stat Statistic) {
+	key := prefix + "\x00" + strconv.Itoa(int(stat.ID))
+
+	name, ok := f.names[key]
+	if !ok {
+		name = prefix + metricName(stat.Description)
+		if _, taken := f.byName[name]; taken {
+			name += "_" + strconv.Itoa(int(stat.ID))
+		}
+
+		f.names[key] = name
+	}
+
+	family, ok := f.byName[name]
+	if !ok {
+		family = &metricFamily{name: name, help: stat.Description}
+		f.byName[name] = family
+		f.families = append(f.families, family)
+	}
+
+	family.samples = append(family.samples, metricSample{uri: uri, value: stat.Value})
+}
+
+func (h *MetricsHandler) render() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, errMetricsHandlerClosed
+	}
+
+	opts := StatisticsOptions{Mode: h.opts.Mode}
+
+	families := &metricFamilies{
+		byName: make(map[string]*metricFamily),
+		names:  make(map[string]string),
+	}
+
+	stats, err := h.session.ConnectionStatistics(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		if h.opts.Filter == nil || h.opts.Filter(stat) {
+			families.add("wiredtiger_", "", stat)
+		}
+	}
+
+	for _, uri := range h.opts.Tables {
+		stats, err := h.session.Statistics(uri, opts)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) || errors.Is(err, ErrorCode(syscall.ENOENT)) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, stat := range stats {
+			if h.opts.Filter == nil || h.opts.Filter(stat) {
+				families.add("wiredtiger_table_", uri, stat)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	for _, family := range families.families {
+		fmt.Fprintf(&buf, "# HELP %s %s\n", family.name, escapeMetricHelp(family.help))
+		fmt.Fprintf(&buf, "# TYPE %s untyped\n", family.name)
+
+		for _, s := range family.samples {
+			if s.uri == "" {
+				fmt.Fprintf(&buf, "%s %d\n", family.name, s.value)
+			} else {
+				fmt.Fprintf(&buf, "%s{uri=\"%s\"} %d\n", family.name, escapeMetricLabel(s.uri), s.value)
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// metricName turns a statistic description such as "cache: bytes read into
+// cache" into a valid metric name like "cache_bytes_read_into_cache".
+func metricName(description string) string {
+	var b strings.Builder
+
+	underscore := true
+
+	for _, r := range strings.ToLower(description) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func escapeMetricHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeMetricLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
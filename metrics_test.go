@@ -0,0 +1,168 @@
+package wtgo_test
+
+import (
+	"bufio"
+	"github.com/dylrich/wtgo"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	metricCommentPattern = regexp.MustCompile(`^# (HELP|TYPE) ([a-zA-Z_:][a-zA-Z0-9_:]*) .*$`)
+	metricSamplePattern  = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{uri="(?:[^"\\]|\\.)*"\})? -?[0-9]+$`)
+)
+
+func TestMetricsHandler(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create,statistics=(fast)", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	for _, k := range []string{"a", "b", "c"} {
+		if err := insert(env.cursor, k, k); err != nil {
+			t.Fatalf("insert %s: %s", k, err)
+		}
+	}
+
+	if err := env.cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	handler, err := env.conn.NewMetricsHandler(wtgo.MetricsOptions{Tables: []string{tablename, "table:missing"}})
+	if err != nil {
+		t.Fatalf("new metrics handler: %s", err)
+	}
+
+	t.Cleanup(func() { handler.Close() })
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape: %s", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("scrape returned status %d", resp.StatusCode)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("scrape returned content type '%s'", ct)
+	}
+
+	types := make(map[string]bool)
+	samples := make(map[string]string)
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := metricCommentPattern.FindStringSubmatch(line); m != nil {
+			if m[1] == "TYPE" {
+				if types[m[2]] {
+					t.Fatalf("metric %s has more than one TYPE line", m[2])
+				}
+
+				types[m[2]] = true
+			}
+
+			continue
+		}
+
+		m := metricSamplePattern.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("invalid exposition line '%s'", line)
+		}
+
+		if !types[m[1]] {
+			t.Fatalf("sample of %s precedes its TYPE line", m[1])
+		}
+
+		fields := strings.Fields(line)
+		samples[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read scrape: %s", err)
+	}
+
+	if len(samples) == 0 {
+		t.Fatalf("scrape returned no samples")
+	}
+
+	sample := `wiredtiger_table_cursor_insert_calls{uri="table:test-table"}`
+	if samples[sample] != "3" {
+		t.Fatalf("%s is '%s', expected 3", sample, samples[sample])
+	}
+
+	for name := range samples {
+		if strings.Contains(name, "table:missing") {
+			t.Fatalf("scrape included missing table: %s", name)
+		}
+	}
+
+	filtered, err := env.conn.NewMetricsHandler(wtgo.MetricsOptions{
+		Filter: func(s wtgo.Statistic) bool { return strings.HasPrefix(s.Description, "cache:") },
+	})
+	if err != nil {
+		t.Fatalf("new filtered metrics handler: %s", err)
+	}
+
+	t.Cleanup(func() { filtered.Close() })
+
+	rec := httptest.NewRecorder()
+	filtered.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("filtered scrape returned status %d", rec.Code)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n") {
+		if !strings.HasPrefix(line, "wiredtiger_cache_") && !strings.Contains(line, " wiredtiger_cache_") {
+			t.Fatalf("filtered scrape returned '%s'", line)
+		}
+	}
+}
+
+func TestMetricsHandlerConnectionClose(t *testing.T) {
+	env, err := newSessionTestEnv("create,statistics=(fast)", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	handler, err := env.conn.NewMetricsHandler(wtgo.MetricsOptions{})
+	if err != nil {
+		t.Fatalf("new metrics handler: %s", err)
+	}
+
+	if err := env.conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("scrape after close returned status %d", rec.Code)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := handler.Close(); err != nil {
+			t.Fatalf("close %d after connection close: %s", i, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Hybrid logical clock timestamps hold wall-clock milliseconds in their
+// upper bits and a logical counter in the lower hlcLogicalBits.
+const hlcLogicalBits = 16
+
+// HLCTimestamp returns the hybrid logical clock timestamp of t with a zero
+// logical counter.
+func HLCTimestamp(t time.Time) uint64 {
+	return uint64(t.UnixMilli()) << hlcLogicalBits
+}
+
+// HLCDuration converts d to a span of hybrid logical clock timestamps, for
+// use as a HistoryWindow.
+func HLCDuration(d time.Duration) uint64 {
+	return uint64(d.Milliseconds()) << hlcLogicalBits
+}
+
+type OracleOptions struct {
+	// HLC derives timestamps from a hybrid logical clock instead of a plain
+	// counter, so that they track wall-clock time.
+	HLC bool
+
+	// Now is the clock read in HLC mode. It defaults to time.Now.
+	Now func() time.Time
+
+	// HistoryWindow is how far the oldest timestamp trails the stable
+	// timestamp. History older than the window may be discarded. Zero moves
+	// oldest up to stable on every Advance, keeping no history, so it must
+	// be set when AdvanceInterval is.
+	HistoryWindow uint64
+
+	// AdvanceInterval, if set, advances the stable and oldest timestamps in
+	// the background.
+	AdvanceInterval time.Duration
+
+	// OnError is called from the background goroutine when advancing
+	// fails.
+	OnError func(err error)
+}
+
+// TimestampOracle hands out strictly increasing commit timestamps and moves
+// the connection's stable timestamp up to the oldest commit still in
+// flight. It is safe for concurrent use.
+type TimestampOracle struct {
+	conn *Connection
+	opts OracleOptions
+
+	mu       sync.Mutex
+	last     uint64
+	inflight map[uint64]struct{}
+
+	// advanceMu serializes Advance so that stable and oldest only move
+	// forward.
+	advanceMu sync.Mutex
+	stable    uint64
+	oldest    uint64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTimestampOracle creates an oracle whose timestamps start after every
+// timestamp already durable or stable on the connection.
+func (conn *Connection) NewTimestampOracle(opts OracleOptions) (*TimestampOracle, error) {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	if opts.AdvanceInterval > 0 && opts.HistoryWindow == 0 {
+		return nil, fmt.Errorf("history window must be set to advance every %s", opts.AdvanceInterval)
+	}
+
+	o := &TimestampOracle{
+		conn:     conn,
+		opts:     opts,
+		inflight: make(map[uint64]struct{}),
+	}
+
+	for _, t := range []ConnectionTimestampType{ConnectionTimestampTypeAllDurable, ConnectionTimestampTypeStable, ConnectionTimestampTypeOldest} {
+		ts, err := conn.QueryTimestamp(t)
+		if err != nil {
+			return nil, err
+		}
+
+		o.last = max(o.last, ts)
+
+		switch t {
+		case ConnectionTimestampTypeStable:
+			o.stable = ts
+		case ConnectionTimestampTypeOldest:
+			o.oldest = ts
+		}
+	}
+
+	if opts.AdvanceInterval > 0 {
+		o.stop = make(chan struct{})
+		o.done = make(chan struct{})
+
+		conn.addWorker(o)
+
+		go o.run()
+	}
+
+	return o, nil
+}
+
+// Reserve returns a new commit timestamp and tracks it as in flight, which
+// holds the stable timestamp below it until Release is called.
+func (o *TimestampOracle) Reserve() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ts := o.last + 1
+
+	if o.opts.HLC {
+		ts = max(ts, HLCTimestamp(o.opts.Now()))
+	}
+
+	o.last = ts
+	o.inflight[ts] = struct{}{}
+
+	return ts
+}
+
+func (o *TimestampOracle) Release(ts uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.inflight, ts)
+}
+
+// Observe moves the clock past ts, e.g. a timestamp received from another
+// node, so that later reservations are greater.
+func (o *TimestampOracle) Observe(ts uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.last = max(o.last, ts)
+}
+
+// CommitTransaction commits the session's running transaction at a newly
+// reserved timestamp and returns it.
+func (o *TimestampOracle) CommitTransaction(s *Session, config string) (uint64, error) {
+	ts := o.Reserve()
+	defer o.Release(ts)
+
+	if err := s.TimestampTransactionUint(TransactionTimestampTypeCommit, ts); err != nil {
+		err = fmt.Errorf("set commit timestamp: %w", err)
+
+		if rerr := s.RollbackTransaction(""); rerr != nil {
+			return 0, errors.Join(err, fmt.Errorf("rollback transaction: %w", rerr))
+		}
+
+		return 0, err
+	}
+
+	if err := s.CommitTransaction(config); err != nil {
+		return 0, err
+	}
+
+	return ts, nil
+}
+
+// Stable returns the timestamp below which no commit is in flight.
+func (o *TimestampOracle) Stable() uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stable := o.last
+
+	for ts := range o.inflight {
+		stable = min(stable, ts-1)
+	}
+
+	return stable
+}
+
+// Advance moves the connection's stable timestamp to Stable and its oldest
+// timestamp to HistoryWindow behind it. Neither ever moves backwards.
+func (o *TimestampOracle) Advance() error {
+	o.advanceMu.Lock()
+	defer o.advanceMu.Unlock()
+
+	var t ConnectionTimestamps
+
+	if stable := o.Stable(); stable > o.stable {
+		t.Stable = stable
+	}
+
+	if stable := max(o.stable, t.Stable); stable > o.opts.HistoryWindow {
+		if oldest := stable - o.opts.HistoryWindow; oldest > o.oldest {
+			t.Oldest = oldest
+		}
+	}
+
+	if err := o.conn.SetTimestamps(t); err != nil {
+		return err
+	}
+
+	o.stable = max(o.stable, t.Stable)
+	o.oldest = max(o.oldest, t.Oldest)
+
+	return nil
+}
+
+// Stop ends background advancing. It is called when the connection closes.
+func (o *TimestampOracle) Stop() error {
+	if o.stop == nil {
+		return nil
+	}
+
+	o.stopOnce.Do(func() {
+		close(o.stop)
+		<-o.done
+
+		o.conn.removeWorker(o)
+	})
+
+	return nil
+}
+
+func (o *TimestampOracle) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.opts.AdvanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		}
+
+		if err := o.Advance(); err != nil && o.opts.OnError != nil {
+			o.opts.OnError(err)
+		}
+	}
+}
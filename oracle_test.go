@@ -0,0 +1,236 @@
+package wtgo_test
+
+import (
+	"github.com/dylrich/wtgo"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimestampOracleReserve(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	oracle, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{})
+	if err != nil {
+		t.Fatalf("new timestamp oracle: %s", err)
+	}
+
+	const workers, n = 8, 100
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	seen := make(map[uint64]bool)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var prev uint64
+
+			for j := 0; j < n; j++ {
+				ts := oracle.Reserve()
+				oracle.Release(ts)
+
+				if ts <= prev {
+					t.Errorf("timestamp %d after %d", ts, prev)
+				}
+
+				prev = ts
+
+				mu.Lock()
+				seen[ts] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(seen) != workers*n {
+		t.Fatalf("reserved %d distinct timestamps, expected %d", len(seen), workers*n)
+	}
+
+	first := oracle.Reserve()
+	second := oracle.Reserve()
+
+	oracle.Release(second)
+
+	if err := oracle.Advance(); err != nil {
+		t.Fatalf("advance: %s", err)
+	}
+
+	if ts, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeStable); err != nil || ts != first-1 {
+		t.Fatalf("stable with %d in flight is %d, %v, expected %d", first, ts, err, first-1)
+	}
+
+	oracle.Release(first)
+
+	if err := oracle.Advance(); err != nil {
+		t.Fatalf("advance: %s", err)
+	}
+
+	if ts, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeStable); err != nil || ts != second {
+		t.Fatalf("stable with nothing in flight is %d, %v, expected %d", ts, err, second)
+	}
+}
+
+func TestTimestampOracleHLC(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	oracle, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{
+		HLC:           true,
+		Now:           func() time.Time { return now },
+		HistoryWindow: wtgo.HLCDuration(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("new timestamp oracle: %s", err)
+	}
+
+	reserve := func() uint64 {
+		ts := oracle.Reserve()
+		oracle.Release(ts)
+
+		return ts
+	}
+
+	base := wtgo.HLCTimestamp(now)
+
+	if ts := reserve(); ts != base {
+		t.Fatalf("first timestamp is %d, expected %d", ts, base)
+	}
+
+	if ts := reserve(); ts != base+1 {
+		t.Fatalf("timestamp within the same millisecond is %d, expected %d", ts, base+1)
+	}
+
+	now = now.Add(-time.Second)
+
+	if ts := reserve(); ts != base+2 {
+		t.Fatalf("timestamp after the clock went back is %d, expected %d", ts, base+2)
+	}
+
+	oracle.Observe(base + 100)
+
+	if ts := reserve(); ts != base+101 {
+		t.Fatalf("timestamp after observe is %d, expected %d", ts, base+101)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	ts := reserve()
+	if ts != wtgo.HLCTimestamp(now) {
+		t.Fatalf("timestamp after the clock moved on is %d, expected %d", ts, wtgo.HLCTimestamp(now))
+	}
+
+	if err := oracle.Advance(); err != nil {
+		t.Fatalf("advance: %s", err)
+	}
+
+	if oldest, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeOldest); err != nil || oldest != ts-wtgo.HLCDuration(time.Minute) {
+		t.Fatalf("oldest is %d, %v, expected a minute behind %d", oldest, err, ts)
+	}
+}
+
+func TestTimestampOracleCommit(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if _, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{AdvanceInterval: time.Millisecond}); err == nil {
+		t.Fatalf("expected error for advancing without a history window")
+	}
+
+	errs := make(chan error, 1)
+
+	oracle, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{
+		HistoryWindow:   10,
+		AdvanceInterval: time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("new timestamp oracle: %s", err)
+	}
+
+	var last uint64
+
+	for i, k := range []string{"a", "b", "c"} {
+		if err := env.session.BeginTransaction(""); err != nil {
+			t.Fatalf("begin transaction %d: %s", i, err)
+		}
+
+		if err := insert(env.cursor, k, k); err != nil {
+			t.Fatalf("insert %d: %s", i, err)
+		}
+
+		ts, err := oracle.CommitTransaction(env.session, "")
+		if err != nil {
+			t.Fatalf("commit %d: %s", i, err)
+		}
+
+		last = ts
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		ts, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeStable)
+		if err != nil {
+			t.Fatalf("query stable: %s", err)
+		}
+
+		if ts == last {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("stable is %d, expected it to reach %d", ts, last)
+		}
+	}
+
+	restarted, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{})
+	if err != nil {
+		t.Fatalf("new second timestamp oracle: %s", err)
+	}
+
+	if ts := restarted.Reserve(); ts <= last {
+		t.Fatalf("second oracle reserved %d, expected more than %d", ts, last)
+	}
+
+	if err := env.conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	select {
+	case err := <-errs:
+		t.Fatalf("advance: %s", err)
+	default:
+	}
+
+	if err := oracle.Stop(); err != nil {
+		t.Fatalf("stop after close: %s", err)
+	}
+}
@@ -0,0 +1,223 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type ParallelScanOptions struct {
+	Partitions    int
+	Samples       int
+	SessionConfig string
+	CursorConfig  string
+
+	// ReadTimestamp, when non-zero, makes every partition read inside a
+	// transaction at this timestamp so the combined result is consistent.
+	ReadTimestamp uint64
+}
+
+type ScanPartition struct {
+	Index  int
+	Lower  []byte
+	Upper  []byte
+	Cursor *Cursor
+}
+
+func (conn *Connection) ParallelScan(uri string, opts ParallelScanOptions, fn func(p *ScanPartition) error) error {
+	partitions := opts.Partitions
+	if partitions <= 0 {
+		partitions = 1
+	}
+
+	samples := opts.Samples
+	if samples <= 0 {
+		samples = partitions * 16
+	}
+
+	splits, err := conn.sampleSplits(uri, opts.SessionConfig, partitions, samples)
+	if err != nil {
+		return fmt.Errorf("sample %s: %w", uri, err)
+	}
+
+	errs := make([]error, len(splits)+1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i <= len(splits); i++ {
+		p := &ScanPartition{Index: i}
+
+		if i > 0 {
+			p.Lower = splits[i-1]
+		}
+
+		if i < len(splits) {
+			p.Upper = splits[i]
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := conn.scanPartition(uri, opts, p, fn); err != nil {
+				errs[p.Index] = fmt.Errorf("partition %d: %w", p.Index, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (conn *Connection) sampleSplits(uri, config string, partitions, samples int) ([][]byte, error) {
+	if partitions == 1 {
+		return nil, nil
+	}
+
+	session, err := conn.OpenSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("open session: %w", err)
+	}
+
+	defer session.Close("")
+
+	cursor, err := session.OpenCursor(uri, "next_random=true")
+	if err != nil {
+		return nil, fmt.Errorf("open random cursor: %w", err)
+	}
+
+	keys := make([][]byte, 0, samples)
+
+	for i := 0; i < samples && cursor.Next(); i++ {
+		key, err := cursor.PackedKey()
+		if err != nil {
+			return nil, fmt.Errorf("get key: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("sample: %w", err)
+	}
+
+	compare, err := newKeyComparer(session, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	equal := func(a, b []byte) bool { return compare.compare(a, b) == 0 }
+
+	slices.SortFunc(keys, compare.compare)
+	keys = slices.CompactFunc(keys, equal)
+
+	if len(keys) < partitions {
+		partitions = len(keys)
+	}
+
+	splits := make([][]byte, 0, partitions)
+
+	for i := 1; i < partitions; i++ {
+		splits = append(splits, keys[i*len(keys)/partitions])
+	}
+
+	splits = slices.CompactFunc(splits, equal)
+
+	if err := compare.close(); err != nil {
+		return nil, fmt.Errorf("compare keys: %w", err)
+	}
+
+	return splits, nil
+}
+
+// keyComparer orders packed keys the way the table does, which differs from
+// their byte order when the table has a custom collator.
+type keyComparer struct {
+	a, b *Cursor
+	err  error
+}
+
+func newKeyComparer(session *Session, uri string) (*keyComparer, error) {
+	a, err := session.OpenCursor(uri, "")
+	if err != nil {
+		return nil, fmt.Errorf("open compare cursor: %w", err)
+	}
+
+	b, err := session.OpenCursor(uri, "")
+	if err != nil {
+		a.Close()
+		return nil, fmt.Errorf("open compare cursor: %w", err)
+	}
+
+	return &keyComparer{a: a, b: b}, nil
+}
+
+func (k *keyComparer) compare(x, y []byte) int {
+	if k.err != nil {
+		return 0
+	}
+
+	k.a.SetPackedKey(x)
+	k.b.SetPackedKey(y)
+
+	c, err := k.a.Compare(k.b)
+	if err != nil {
+		k.err = err
+		return 0
+	}
+
+	return int(c)
+}
+
+// close closes the cursors and returns the first comparison error.
+func (k *keyComparer) close() error {
+	return errors.Join(k.err, k.a.Close(), k.b.Close())
+}
+
+func (conn *Connection) scanPartition(uri string, opts ParallelScanOptions, p *ScanPartition, fn func(p *ScanPartition) error) error {
+	session, err := conn.OpenSession(opts.SessionConfig)
+	if err != nil {
+		return fmt.Errorf("open session: %w", err)
+	}
+
+	defer session.Close("")
+
+	if opts.ReadTimestamp != 0 {
+		if err := session.BeginTransaction(fmt.Sprintf("read_timestamp=%x", opts.ReadTimestamp)); err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+
+		defer session.RollbackTransaction("")
+	}
+
+	cursor, err := session.OpenCursor(uri, opts.CursorConfig)
+	if err != nil {
+		return fmt.Errorf("open cursor: %w", err)
+	}
+
+	defer cursor.Close()
+
+	if p.Lower != nil {
+		cursor.SetPackedKey(p.Lower)
+
+		if err := cursor.Bound("action=set,bound=lower,inclusive=true"); err != nil {
+			return fmt.Errorf("set lower bound: %w", err)
+		}
+	}
+
+	if p.Upper != nil {
+		cursor.SetPackedKey(p.Upper)
+
+		if err := cursor.Bound("action=set,bound=upper,inclusive=false"); err != nil {
+			return fmt.Errorf("set upper bound: %w", err)
+		}
+	}
+
+	cursor.keybuf = cursor.keybuf[:0]
+	p.Cursor = cursor
+
+	return fn(p)
+}
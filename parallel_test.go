@@ -0,0 +1,209 @@
+package wtgo_test
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParallelScan(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=Q,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	insertAt := func(ts uint64, from, to uint64) {
+		if err := env.session.BeginTransaction(""); err != nil {
+			t.Fatalf("begin transaction: %s", err)
+		}
+
+		for k := from; k < to; k++ {
+			if err := insert(env.cursor, k, fmt.Sprintf("value-%d", k)); err != nil {
+				t.Fatalf("insert %d: %s", k, err)
+			}
+		}
+
+		if err := env.session.TimestampTransactionUint(wtgo.TransactionTimestampTypeCommit, ts); err != nil {
+			t.Fatalf("timestamp transaction: %s", err)
+		}
+
+		if err := env.session.CommitTransaction(""); err != nil {
+			t.Fatalf("commit transaction: %s", err)
+		}
+	}
+
+	insertAt(10, 0, 1000)
+	insertAt(20, 1000, 1500)
+
+	cases := map[string]struct {
+		opts wtgo.ParallelScanOptions
+		want uint64
+	}{
+		"single": {
+			opts: wtgo.ParallelScanOptions{Partitions: 1},
+			want: 1500,
+		},
+		"partitioned": {
+			opts: wtgo.ParallelScanOptions{Partitions: 4},
+			want: 1500,
+		},
+		"read-timestamp": {
+			opts: wtgo.ParallelScanOptions{Partitions: 4, ReadTimestamp: 15},
+			want: 1000,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			keys := make([]uint64, 0, tc.want)
+			seen := make(map[int]bool)
+
+			err := env.conn.ParallelScan(tablename, tc.opts, func(p *wtgo.ScanPartition) error {
+				found := make([]uint64, 0, 256)
+
+				for p.Cursor.Next() {
+					var k uint64
+
+					if err := p.Cursor.GetKey(&k); err != nil {
+						return err
+					}
+
+					found = append(found, k)
+				}
+
+				if err := p.Cursor.Err(); err != nil {
+					return err
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				seen[p.Index] = true
+				keys = append(keys, found...)
+
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("parallel scan: %s", err)
+			}
+
+			if len(seen) > tc.opts.Partitions {
+				t.Fatalf("scanned %d partitions, wanted at most %d", len(seen), tc.opts.Partitions)
+			}
+
+			slices.Sort(keys)
+
+			want := make([]uint64, tc.want)
+			for i := range want {
+				want[i] = uint64(i)
+			}
+
+			if diff := cmp.Diff(want, keys); diff != "" {
+				t.Fatalf("scanned keys don't match (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		err := env.conn.ParallelScan(tablename, wtgo.ParallelScanOptions{Partitions: 4}, func(p *wtgo.ScanPartition) error {
+			return fmt.Errorf("failed %d", p.Index)
+		})
+		if err == nil {
+			t.Fatalf("expected combined error")
+		}
+	})
+	t.Run("collator", func(t *testing.T) {
+		reverse, err := wtgo.DecodedCollator("Q", func(a, b []any) int {
+			x, y := a[0].(uint64), b[0].(uint64)
+
+			switch {
+			case x > y:
+				return -1
+			case x < y:
+				return 1
+			default:
+				return 0
+			}
+		})
+		if err != nil {
+			t.Fatalf("decoded collator: %s", err)
+		}
+
+		if err := env.conn.AddCollator("reverse", reverse); err != nil {
+			t.Fatalf("add reverse collator: %s", err)
+		}
+
+		reversed := "table:reversed"
+
+		if err := env.session.Create(reversed, "key_format=Q,value_format=S,collator=reverse"); err != nil {
+			t.Fatalf("create table: %s", err)
+		}
+
+		cursor, err := env.session.OpenCursor(reversed, "")
+		if err != nil {
+			t.Fatalf("open cursor: %s", err)
+		}
+
+		for k := uint64(0); k < 500; k++ {
+			if err := insert(cursor, k, fmt.Sprintf("value-%d", k)); err != nil {
+				t.Fatalf("insert %d: %s", k, err)
+			}
+		}
+
+		if err := cursor.Close(); err != nil {
+			t.Fatalf("close cursor: %s", err)
+		}
+
+		var mu sync.Mutex
+		keys := make([]uint64, 0, 500)
+
+		err = env.conn.ParallelScan(reversed, wtgo.ParallelScanOptions{Partitions: 4}, func(p *wtgo.ScanPartition) error {
+			found := make([]uint64, 0, 256)
+
+			for p.Cursor.Next() {
+				var k uint64
+
+				if err := p.Cursor.GetKey(&k); err != nil {
+					return err
+				}
+
+				found = append(found, k)
+			}
+
+			if err := p.Cursor.Err(); err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			keys = append(keys, found...)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("parallel scan: %s", err)
+		}
+
+		slices.Sort(keys)
+
+		want := make([]uint64, 500)
+		for i := range want {
+			want[i] = uint64(i)
+		}
+
+		if diff := cmp.Diff(want, keys); diff != "" {
+			t.Fatalf("scanned keys don't match (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package wtgo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+var ErrTimestampTooOld = errors.New("timestamp is older than the oldest timestamp")
+
+// BeginReadTransaction begins a transaction that reads the database as of
+// ts. It fails with ErrTimestampTooOld when the history at ts may already
+// have been discarded.
+func (s *Session) BeginReadTransaction(ts uint64) error {
+	if ts == 0 {
+		return fmt.Errorf("read timestamp must not be zero")
+	}
+
+	err := s.BeginTransaction("read_timestamp=" + strconv.FormatUint(ts, 16))
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, ErrorCode(syscall.EINVAL)) {
+		oldest, qerr := s.conn.QueryTimestamp(ConnectionTimestampTypeOldest)
+		if qerr == nil && ts < oldest {
+			return fmt.Errorf("read at %d: %w %d", ts, ErrTimestampTooOld, oldest)
+		}
+	}
+
+	return fmt.Errorf("read at %d: %w", ts, err)
+}
+
+// Snapshot is a read-only view of the database at a timestamp, backed by a
+// transaction on its own session. Like a Session, it must not be used by
+// several goroutines at once.
+type Snapshot struct {
+	session *Session
+	ts      uint64
+}
+
+func (conn *Connection) ReadAt(ts uint64) (*Snapshot, error) {
+	session, err := conn.OpenSession("")
+	if err != nil {
+		return nil, fmt.Errorf("open snapshot session: %w", err)
+	}
+
+	if err := session.BeginReadTransaction(ts); err != nil {
+		session.Close("")
+		return nil, err
+	}
+
+	return &Snapshot{session: session, ts: ts}, nil
+}
+
+// ReadAtTime reads the database as of t. It is only meaningful when commit
+// timestamps come from a TimestampOracle in HLC mode.
+func (conn *Connection) ReadAtTime(t time.Time) (*Snapshot, error) {
+	return conn.ReadAt(HLCTimestamp(t))
+}
+
+func (s *Snapshot) Timestamp() uint64 {
+	return s.ts
+}
+
+// OpenCursor opens a read-only cursor on uri as of the snapshot's
+// timestamp.
+func (s *Snapshot) OpenCursor(uri, config string) (*Cursor, error) {
+	c := "readonly=true"
+	if config != "" {
+		c += "," + config
+	}
+
+	return s.session.OpenCursor(uri, c)
+}
+
+// Close ends the snapshot's transaction and closes its session along with
+// every cursor opened on it.
+func (s *Snapshot) Close() error {
+	if err := s.session.RollbackTransaction(""); err != nil {
+		s.session.Close("")
+		return fmt.Errorf("rollback snapshot transaction: %w", err)
+	}
+
+	return s.session.Close("")
+}
@@ -0,0 +1,158 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"testing"
+	"time"
+)
+
+func TestReadAt(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := commitAt(env.session, env.cursor, 10, "a", "1"); err != nil {
+		t.Fatalf("commit at 10: %s", err)
+	}
+
+	if err := commitAt(env.session, env.cursor, 20, "a", "2"); err != nil {
+		t.Fatalf("commit at 20: %s", err)
+	}
+
+	if err := env.conn.SetTimestamps(wtgo.ConnectionTimestamps{Oldest: 5, Stable: 20}); err != nil {
+		t.Fatalf("set timestamps: %s", err)
+	}
+
+	cases := map[string]struct {
+		ts   uint64
+		want string
+		err  error
+	}{
+		"before first commit": {ts: 9, err: wtgo.ErrNotFound},
+		"at first commit":     {ts: 10, want: "1"},
+		"between commits":     {ts: 15, want: "1"},
+		"after second commit": {ts: 25, want: "2"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			snapshot, err := env.conn.ReadAt(tc.ts)
+			if err != nil {
+				t.Fatalf("read at %d: %s", tc.ts, err)
+			}
+
+			defer snapshot.Close()
+
+			cursor, err := snapshot.OpenCursor(tablename, "")
+			if err != nil {
+				t.Fatalf("open cursor: %s", err)
+			}
+
+			r, err := searchKey[string, string](cursor, "a")
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("search returned err '%v', expected '%s'", err, tc.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("search: %s", err)
+			}
+
+			if r.Value != tc.want {
+				t.Fatalf("value at %d is '%s', expected '%s'", tc.ts, r.Value, tc.want)
+			}
+		})
+	}
+
+	if _, err := env.conn.ReadAt(4); !errors.Is(err, wtgo.ErrTimestampTooOld) {
+		t.Fatalf("read before oldest returned err '%v', expected timestamp too old", err)
+	}
+
+	snapshot, err := env.conn.ReadAt(15)
+	if err != nil {
+		t.Fatalf("read at 15: %s", err)
+	}
+
+	cursor, err := snapshot.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	if err := insert(cursor, "b", "3"); err == nil {
+		t.Fatalf("insert through a snapshot cursor succeeded")
+	}
+
+	if err := snapshot.Close(); err != nil {
+		t.Fatalf("close snapshot: %s", err)
+	}
+}
+
+func TestReadAtTime(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	oracle, err := env.conn.NewTimestampOracle(wtgo.OracleOptions{HLC: true, Now: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("new timestamp oracle: %s", err)
+	}
+
+	if err := env.session.BeginTransaction(""); err != nil {
+		t.Fatalf("begin transaction: %s", err)
+	}
+
+	if err := insert(env.cursor, "a", "1"); err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	if _, err := oracle.CommitTransaction(env.session, ""); err != nil {
+		t.Fatalf("commit: %s", err)
+	}
+
+	for at, found := range map[time.Time]bool{
+		now.Add(-time.Millisecond): false,
+		now:                        true,
+		now.Add(time.Second):       true,
+	} {
+		snapshot, err := env.conn.ReadAtTime(at)
+		if err != nil {
+			t.Fatalf("read at %s: %s", at, err)
+		}
+
+		cursor, err := snapshot.OpenCursor(tablename, "")
+		if err != nil {
+			t.Fatalf("open cursor: %s", err)
+		}
+
+		_, err = searchKey[string, string](cursor, "a")
+		if found && err != nil {
+			t.Fatalf("search at %s: %s", at, err)
+		}
+
+		if !found && !errors.Is(err, wtgo.ErrNotFound) {
+			t.Fatalf("search at %s returned err '%v', expected not found", at, err)
+		}
+
+		if err := snapshot.Close(); err != nil {
+			t.Fatalf("close snapshot: %s", err)
+		}
+	}
+}
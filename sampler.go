@@ -0,0 +1,311 @@
+package wtgo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type SamplerOptions struct {
+	// Interval between samples. It defaults to one second.
+	Interval time.Duration
+
+	// Size is the number of samples kept. It defaults to 60.
+	Size int
+
+	// Tables lists the table or index uris sampled next to the connection.
+	Tables []string
+
+	Mode StatisticsMode
+
+	Thresholds []Threshold
+
+	// OnError is called from the sampler's goroutine when a sample cannot
+	// be read. Sampling continues at the next interval.
+	OnError func(err error)
+}
+
+// Threshold watches a value derived from the samples, such as a Rate, and
+// calls Fn when it rises above Limit and again when it falls back. Value
+// and Fn are called from the sampler's goroutine and must not call Stop.
+type Threshold struct {
+	Name  string
+	Value func(s *Sampler) (float64, bool)
+	Limit float64
+	Fn    func(e ThresholdEvent)
+}
+
+type ThresholdEvent struct {
+	Name  string
+	Time  time.Time
+	Value float64
+
+	// Crossed is true when the value rose above the limit and false when
+	// it fell back.
+	Crossed bool
+}
+
+// Sample holds the statistics read at one point in time, keyed by uri. The
+// connection's statistics have the empty uri.
+type Sample struct {
+	Time       time.Time
+	Statistics map[string]Statistics
+}
+
+func (s Sample) Value(uri, description string) (int64, bool) {
+	stat, ok := s.Statistics[uri].ByDescription(description)
+	return stat.Value, ok
+}
+
+// Sampler periodically reads statistics on its own session and keeps the
+// most recent samples in a ring buffer. It is stopped with Stop or when the
+// connection is closed.
+type Sampler struct {
+	conn    *Connection
+	session *Session
+	opts    SamplerOptions
+
+	mu      sync.Mutex
+	samples []Sample
+	next    int
+	n       int
+
+	crossed []bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	stopErr  error
+}
+
+func (conn *Connection) StartSampler(opts SamplerOptions) (*Sampler, error) {
+	if opts.Interval == 0 {
+		opts.Interval = time.Second
+	}
+
+	if opts.Size == 0 {
+		opts.Size = 60
+	}
+
+	if opts.Interval < 0 || opts.Size < 0 {
+		return nil, fmt.Errorf("invalid sampler interval %s or size %d", opts.Interval, opts.Size)
+	}
+
+	for _, t := range opts.Thresholds {
+		if t.Value == nil {
+			return nil, fmt.Errorf("threshold %s has no value", t.Name)
+		}
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		return nil, fmt.Errorf("open sampler session: %w", err)
+	}
+
+	s := &Sampler{
+		conn:    conn,
+		session: session,
+		opts:    opts,
+		samples: make([]Sample, opts.Size),
+		crossed: make([]bool, len(opts.Thresholds)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	conn.addWorker(s)
+
+	go s.run()
+
+	return s, nil
+}
+
+// Stop ends sampling and closes the sampler's session. The samples remain
+// readable.
+func (s *Sampler) Stop() error {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+
+		s.conn.removeWorker(s)
+
+		s.stopErr = s.session.Close("")
+	})
+
+	return s.stopErr
+}
+
+func (s *Sampler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.sample()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sampler) sample() {
+	sample, err := s.read()
+	if err != nil {
+		if s.opts.OnError != nil {
+			s.opts.OnError(err)
+		}
+
+		return
+	}
+
+	s.mu.Lock()
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % len(s.samples)
+	s.n = min(s.n+1, len(s.samples))
+	s.mu.Unlock()
+
+	for i, t := range s.opts.Thresholds {
+		value, ok := t.Value(s)
+		if !ok {
+			continue
+		}
+
+		crossed := value > t.Limit
+		if crossed == s.crossed[i] {
+			continue
+		}
+
+		s.crossed[i] = crossed
+
+		if t.Fn != nil {
+			t.Fn(ThresholdEvent{Name: t.Name, Time: sample.Time, Value: value, Crossed: crossed})
+		}
+	}
+}
+
+func (s *Sampler) read() (Sample, error) {
+	opts := StatisticsOptions{Mode: s.opts.Mode}
+
+	sample := Sample{
+		Time:       time.Now(),
+		Statistics: make(map[string]Statistics, len(s.opts.Tables)+1),
+	}
+
+	stats, err := s.session.ConnectionStatistics(opts)
+	if err != nil {
+		return Sample{}, err
+	}
+
+	sample.Statistics[""] = stats
+
+	for _, uri := range s.opts.Tables {
+		stats, err := s.session.Statistics(uri, opts)
+		if err != nil {
+			return Sample{}, err
+		}
+
+		sample.Statistics[uri] = stats
+	}
+
+	return sample, nil
+}
+
+// Samples returns the buffered samples, oldest first.
+func (s *Sampler) Samples() []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := make([]Sample, 0, s.n)
+
+	for i := 0; i < s.n; i++ {
+		samples = append(samples, s.samples[(s.next-s.n+i+len(s.samples))%len(s.samples)])
+	}
+
+	return samples
+}
+
+func (s *Sampler) Latest() (Sample, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.n == 0 {
+		return Sample{}, false
+	}
+
+	return s.samples[(s.next-1+len(s.samples))%len(s.samples)], true
+}
+
+// window returns the buffered samples taken within d of the latest one,
+// oldest first.
+func (s *Sampler) window(d time.Duration) []Sample {
+	samples := s.Samples()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	latest := samples[len(samples)-1].Time
+
+	for i, sample := range samples {
+		if latest.Sub(sample.Time) <= d {
+			return samples[i:]
+		}
+	}
+
+	return nil
+}
+
+// Delta returns how much a statistic changed over the window ending at the
+// latest sample, and the time the window actually spans.
+func (s *Sampler) Delta(uri, description string, window time.Duration) (int64, time.Duration, bool) {
+	samples := s.window(window)
+	if len(samples) < 2 {
+		return 0, 0, false
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+
+	from, ok := first.Value(uri, description)
+	if !ok {
+		return 0, 0, false
+	}
+
+	to, ok := last.Value(uri, description)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return to - from, last.Time.Sub(first.Time), true
+}
+
+// Rate returns the per-second change of a counter over the window.
+func (s *Sampler) Rate(uri, description string, window time.Duration) (float64, bool) {
+	delta, elapsed, ok := s.Delta(uri, description, window)
+	if !ok || elapsed <= 0 {
+		return 0, false
+	}
+
+	return float64(delta) / elapsed.Seconds(), true
+}
+
+// Average returns the mean of a gauge, such as the bytes in the cache, over
+// the window.
+func (s *Sampler) Average(uri, description string, window time.Duration) (float64, bool) {
+	var sum float64
+	var n int
+
+	for _, sample := range s.window(window) {
+		if v, ok := sample.Value(uri, description); ok {
+			sum += float64(v)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0, false
+	}
+
+	return sum / float64(n), true
+}
@@ -0,0 +1,155 @@
+package wtgo_test
+
+import (
+	"github.com/dylrich/wtgo"
+	"testing"
+	"time"
+)
+
+func TestSampler(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+	inserts := "cursor: insert calls"
+
+	env, err := newTableCursorTestEnv("create,statistics=(fast)", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	events := make(chan wtgo.ThresholdEvent, 16)
+
+	sampler, err := env.conn.StartSampler(wtgo.SamplerOptions{
+		Interval: 10 * time.Millisecond,
+		Size:     5,
+		Tables:   []string{tablename},
+		Thresholds: []wtgo.Threshold{{
+			Name: "inserts",
+			Value: func(s *wtgo.Sampler) (float64, bool) {
+				return s.Rate(tablename, inserts, time.Minute)
+			},
+			Limit: 0,
+			Fn: func(e wtgo.ThresholdEvent) {
+				select {
+				case events <- e:
+				default:
+				}
+			},
+		}},
+		OnError: func(err error) { t.Errorf("sample: %s", err) },
+	})
+	if err != nil {
+		t.Fatalf("start sampler: %s", err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		if _, ok := sampler.Latest(); ok {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("no sample was taken")
+		}
+	}
+
+	for i, k := range []string{"a", "b", "c"} {
+		if err := insert(env.cursor, k, k); err != nil {
+			t.Fatalf("insert %d: %s", i, err)
+		}
+	}
+
+	if err := env.cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	select {
+	case e := <-events:
+		if e.Name != "inserts" || !e.Crossed || e.Value <= 0 {
+			t.Fatalf("unexpected threshold event %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("threshold was not crossed")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	samples := sampler.Samples()
+	if len(samples) != 5 {
+		t.Fatalf("sampler kept %d samples, expected 5", len(samples))
+	}
+
+	for i := 1; i < len(samples); i++ {
+		if !samples[i].Time.After(samples[i-1].Time) {
+			t.Fatalf("samples are not ordered oldest first")
+		}
+	}
+
+	latest, ok := sampler.Latest()
+	if !ok || !latest.Time.Equal(samples[len(samples)-1].Time) {
+		t.Fatalf("latest sample doesn't match the newest buffered sample")
+	}
+
+	if v, ok := latest.Value(tablename, inserts); !ok || v != 3 {
+		t.Fatalf("'%s' is %d, expected 3", inserts, v)
+	}
+
+	if delta, _, ok := sampler.Delta(tablename, inserts, time.Minute); !ok || delta != 0 {
+		t.Fatalf("delta after inserts stopped is %d, expected 0", delta)
+	}
+
+	select {
+	case e := <-events:
+		if e.Crossed {
+			t.Fatalf("unexpected threshold event %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("threshold did not fall back")
+	}
+
+	if err := env.conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+
+	if err := sampler.Stop(); err != nil {
+		t.Fatalf("stop sampler after close: %s", err)
+	}
+
+	n := len(sampler.Samples())
+
+	time.Sleep(50 * time.Millisecond)
+
+	if len(sampler.Samples()) != n {
+		t.Fatalf("sampler kept sampling after the connection closed")
+	}
+}
+
+func TestSamplerStop(t *testing.T) {
+	dir := t.TempDir()
+
+	conn, err := openTestConnection(dir, "create,statistics=(fast)", wtgo.OpenOptions{})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	sampler, err := conn.StartSampler(wtgo.SamplerOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("start sampler: %s", err)
+	}
+
+	if err := sampler.Stop(); err != nil {
+		t.Fatalf("stop sampler: %s", err)
+	}
+
+	if err := sampler.Stop(); err != nil {
+		t.Fatalf("stop sampler twice: %s", err)
+	}
+
+	if _, ok := sampler.Latest(); !ok {
+		t.Fatalf("no sample was taken before stop")
+	}
+
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close connection: %s", err)
+	}
+}
@@ -0,0 +1,231 @@
+package wtgo
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+type ScanDirection uint8
+
+const (
+	ScanDirectionForward  ScanDirection = 1
+	ScanDirectionBackward ScanDirection = 2
+)
+
+const scanTokenVersion = 1
+
+var ErrInvalidScanToken = errors.New("invalid scan token")
+
+// ScanToken encodes the cursor's current position and bounds so that a scan
+// in direction dir can be resumed later with Session.ResumeScan.
+func (c *Cursor) ScanToken(dir ScanDirection) (string, error) {
+	if dir != ScanDirectionForward && dir != ScanDirectionBackward {
+		return "", fmt.Errorf("unknown scan direction %d", dir)
+	}
+
+	key, err := c.PackedKey()
+	if err != nil {
+		return "", fmt.Errorf("get key: %w", err)
+	}
+
+	buf := make([]byte, 0, 2+len(c.URI())+len(key)+len(c.lower.key)+len(c.upper.key)+5*binary.MaxVarintLen64)
+	buf = append(buf, scanTokenVersion, byte(dir))
+	buf = appendTokenBytes(buf, []byte(c.URI()))
+	buf = appendTokenBytes(buf, key)
+	buf = appendTokenBound(buf, c.lower)
+	buf = appendTokenBound(buf, c.upper)
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ResumeScan opens a cursor on uri that continues the scan recorded in
+// token. The next call to Next (forward) or Prev (backward) returns the
+// first row strictly after the tokenized position, within the original
+// bounds. Tokens are not authenticated, so a token recorded on another uri
+// is rejected rather than trusted.
+func (s *Session) ResumeScan(uri, token, config string) (*Cursor, ScanDirection, error) {
+	t, err := decodeScanToken(token)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if t.uri != uri {
+		return nil, 0, fmt.Errorf("%w: token was recorded on another uri than %s", ErrInvalidScanToken, uri)
+	}
+
+	cursor, err := s.OpenCursor(t.uri, config)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open cursor: %w", err)
+	}
+
+	if err := cursor.resume(t); err != nil {
+		cursor.Close()
+		return nil, 0, err
+	}
+
+	return cursor, t.dir, nil
+}
+
+func (c *Cursor) resume(t *scanToken) error {
+	if err := c.CheckPackedKey(t.key); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScanToken, err)
+	}
+
+	for _, b := range []struct {
+		name  string
+		bound cursorBound
+	}{
+		{name: "lower", bound: t.lower},
+		{name: "upper", bound: t.upper},
+	} {
+		if !b.bound.set {
+			continue
+		}
+
+		c.SetPackedKey(b.bound.key)
+
+		config := fmt.Sprintf("action=set,bound=%s,inclusive=%t", b.name, b.bound.inclusive)
+		if err := c.Bound(config); err != nil {
+			return fmt.Errorf("set %s bound: %w", b.name, err)
+		}
+	}
+
+	// Narrow the scan with an exclusive bound at the last returned key. It
+	// is applied below Bound so the original bounds are kept for new tokens.
+	which := "lower"
+	if t.dir == ScanDirectionBackward {
+		which = "upper"
+	}
+
+	if err := c.bound(fmt.Sprintf("action=set,bound=%s,inclusive=false", which), t.key); err != nil {
+		return fmt.Errorf("set resume bound: %w", err)
+	}
+
+	c.keybuf = c.keybuf[:0]
+
+	return nil
+}
+
+type scanToken struct {
+	dir   ScanDirection
+	uri   string
+	key   []byte
+	lower cursorBound
+	upper cursorBound
+}
+
+func decodeScanToken(token string) (*scanToken, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScanToken, err)
+	}
+
+	if len(buf) < 2 {
+		return nil, ErrInvalidScanToken
+	}
+
+	if buf[0] != scanTokenVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidScanToken, buf[0])
+	}
+
+	t := &scanToken{dir: ScanDirection(buf[1])}
+
+	if t.dir != ScanDirectionForward && t.dir != ScanDirectionBackward {
+		return nil, fmt.Errorf("%w: unknown direction %d", ErrInvalidScanToken, t.dir)
+	}
+
+	buf = buf[2:]
+
+	var uri []byte
+
+	if uri, buf, err = readTokenBytes(buf); err != nil {
+		return nil, err
+	}
+
+	if t.key, buf, err = readTokenBytes(buf); err != nil {
+		return nil, err
+	}
+
+	if t.lower, buf, err = readTokenBound(buf); err != nil {
+		return nil, err
+	}
+
+	if t.upper, buf, err = readTokenBound(buf); err != nil {
+		return nil, err
+	}
+
+	if len(buf) != 0 || len(uri) == 0 || len(t.key) == 0 {
+		return nil, ErrInvalidScanToken
+	}
+
+	t.uri = string(uri)
+
+	return t, nil
+}
+
+func appendTokenBytes(buf, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func readTokenBytes(buf []byte) ([]byte, []byte, error) {
+	n, size := binary.Uvarint(buf)
+	if size <= 0 || uint64(len(buf)-size) < n {
+		return nil, nil, ErrInvalidScanToken
+	}
+
+	buf = buf[size:]
+
+	return buf[:n], buf[n:], nil
+}
+
+const (
+	tokenBoundUnset     = 0
+	tokenBoundExclusive = 1
+	tokenBoundInclusive = 2
+)
+
+func appendTokenBound(buf []byte, b cursorBound) []byte {
+	switch {
+	case !b.set:
+		return append(buf, tokenBoundUnset)
+	case b.inclusive:
+		buf = append(buf, tokenBoundInclusive)
+	default:
+		buf = append(buf, tokenBoundExclusive)
+	}
+
+	return appendTokenBytes(buf, b.key)
+}
+
+func readTokenBound(buf []byte) (cursorBound, []byte, error) {
+	if len(buf) == 0 {
+		return cursorBound{}, nil, ErrInvalidScanToken
+	}
+
+	kind := buf[0]
+	buf = buf[1:]
+
+	switch kind {
+	case tokenBoundUnset:
+		return cursorBound{}, buf, nil
+	case tokenBoundExclusive, tokenBoundInclusive:
+	default:
+		return cursorBound{}, nil, ErrInvalidScanToken
+	}
+
+	key, buf, err := readTokenBytes(buf)
+	if err != nil {
+		return cursorBound{}, nil, err
+	}
+
+	b := cursorBound{
+		key:       key,
+		inclusive: kind == tokenBoundInclusive,
+		set:       true,
+	}
+
+	return b, buf, nil
+}
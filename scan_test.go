@@ -0,0 +1,184 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResumeScan(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	records := make([]record, 0, 10)
+
+	for _, char := range "abcdefghij" {
+		records = append(records, record{k: []any{string(char)}, v: []any{string(char)}})
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	cases := map[string]struct {
+		dir  wtgo.ScanDirection
+		want []string
+	}{
+		"forward": {
+			dir:  wtgo.ScanDirectionForward,
+			want: []string{"b", "c", "d", "e", "f", "g"},
+		},
+		"backward": {
+			dir:  wtgo.ScanDirectionBackward,
+			want: []string{"g", "f", "e", "d", "c", "b"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cursor, err := env.session.OpenCursor(tablename, "")
+			if err != nil {
+				t.Fatalf("open cursor: %s", err)
+			}
+
+			if err := cursor.SetKey("b"); err != nil {
+				t.Fatalf("set lower key: %s", err)
+			}
+
+			if err := cursor.Bound("action=set,bound=lower,inclusive=true"); err != nil {
+				t.Fatalf("set lower bound: %s", err)
+			}
+
+			if err := cursor.SetKey("h"); err != nil {
+				t.Fatalf("set upper key: %s", err)
+			}
+
+			if err := cursor.Bound("action=set,bound=upper,inclusive=false"); err != nil {
+				t.Fatalf("set upper bound: %s", err)
+			}
+
+			got := make([]string, 0, len(tc.want))
+			pages := 0
+
+			for {
+				page, token, err := scanPage(cursor, tc.dir, 2)
+				if err != nil {
+					t.Fatalf("scan page %d: %s", pages, err)
+				}
+
+				if err := cursor.Close(); err != nil {
+					t.Fatalf("close cursor: %s", err)
+				}
+
+				got = append(got, page...)
+				pages++
+
+				if token == "" {
+					break
+				}
+
+				session, err := env.conn.OpenSession("")
+				if err != nil {
+					t.Fatalf("open session: %s", err)
+				}
+
+				t.Cleanup(func() { session.Close("") })
+
+				var dir wtgo.ScanDirection
+
+				cursor, dir, err = session.ResumeScan(tablename, token, "")
+				if err != nil {
+					t.Fatalf("resume scan: %s", err)
+				}
+
+				if dir != tc.dir {
+					t.Fatalf("resumed in direction %d, wanted %d", dir, tc.dir)
+				}
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("scanned keys don't match (-want +got):\n%s", diff)
+			}
+
+			if pages != 4 {
+				t.Fatalf("scanned %d pages, wanted 4", pages)
+			}
+		})
+	}
+
+	t.Run("invalid-token", func(t *testing.T) {
+		for _, token := range []string{"", "not a token", "AgE"} {
+			if _, _, err := env.session.ResumeScan(tablename, token, ""); !errors.Is(err, wtgo.ErrInvalidScanToken) {
+				t.Fatalf("resume from %q returned err '%v', expected invalid scan token", token, err)
+			}
+		}
+	})
+
+	t.Run("other-uri", func(t *testing.T) {
+		if err := env.cursor.SetKey("c"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		token, err := env.cursor.ScanToken(wtgo.ScanDirectionForward)
+		if err != nil {
+			t.Fatalf("scan token: %s", err)
+		}
+
+		if err := env.cursor.Reset(); err != nil {
+			t.Fatalf("reset: %s", err)
+		}
+
+		for _, uri := range []string{"metadata:", "table:other-table"} {
+			if _, _, err := env.session.ResumeScan(uri, token, ""); !errors.Is(err, wtgo.ErrInvalidScanToken) {
+				t.Fatalf("resume on %s returned err '%v', expected invalid scan token", uri, err)
+			}
+		}
+	})
+}
+
+func scanPage(cursor *wtgo.Cursor, dir wtgo.ScanDirection, size int) ([]string, string, error) {
+	it := cursor.Next
+	if dir == wtgo.ScanDirectionBackward {
+		it = cursor.Prev
+	}
+
+	page := make([]string, 0, size)
+
+	for len(page) < size && it() {
+		var k string
+
+		if err := cursor.GetKey(&k); err != nil {
+			return nil, "", err
+		}
+
+		page = append(page, k)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(page) < size {
+		return page, "", nil
+	}
+
+	token, err := cursor.ScanToken(dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return page, token, nil
+}
@@ -0,0 +1,190 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"strings"
+)
+
+type StatisticsMode string
+
+const (
+	// StatisticsDefault gathers statistics at the level the connection was
+	// opened with.
+	StatisticsDefault StatisticsMode = ""
+	StatisticsFast    StatisticsMode = "fast"
+	StatisticsAll     StatisticsMode = "all"
+)
+
+type StatisticsOptions struct {
+	Mode StatisticsMode
+
+	// Clear resets the statistics after they are read.
+	Clear bool
+}
+
+func (o StatisticsOptions) config() string {
+	var parts []string
+
+	if o.Mode != StatisticsDefault {
+		parts = append(parts, string(o.Mode))
+	}
+
+	if o.Clear {
+		parts = append(parts, "clear")
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "statistics=(" + strings.Join(parts, ",") + ")"
+}
+
+// Statistic is one row of a statistics cursor. IDs are the WT_STAT_*
+// constants of the linked WiredTiger version and are not stable across
+// releases; Description is the safer key to look statistics up by.
+type Statistic struct {
+	ID          int32
+	Description string
+	Printable   string
+	Value       int64
+}
+
+type Statistics []Statistic
+
+func (s Statistics) ByID(id int32) (Statistic, bool) {
+	for _, stat := range s {
+		if stat.ID == id {
+			return stat, true
+		}
+	}
+
+	return Statistic{}, false
+}
+
+func (s Statistics) ByDescription(description string) (Statistic, bool) {
+	for _, stat := range s {
+		if stat.Description == description {
+			return stat, true
+		}
+	}
+
+	return Statistic{}, false
+}
+
+// ConnectionStatistics returns the statistics of the whole connection. The
+// connection must have been opened with statistics enabled.
+func (s *Session) ConnectionStatistics(opts StatisticsOptions) (Statistics, error) {
+	return s.statistics("statistics:", opts)
+}
+
+// Statistics returns the statistics of a table, index, column group or file
+// uri.
+func (s *Session) Statistics(uri string, opts StatisticsOptions) (Statistics, error) {
+	return s.statistics("statistics:"+uri, opts)
+}
+
+// SessionStatistics returns the statistics of this session.
+func (s *Session) SessionStatistics(opts StatisticsOptions) (Statistics, error) {
+	return s.statistics("statistics:session", opts)
+}
+
+func (s *Session) statistics(uri string, opts StatisticsOptions) (Statistics, error) {
+	cursor, err := s.OpenCursor(uri, opts.config())
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", uri, err)
+	}
+
+	defer cursor.Close()
+
+	stats := make(Statistics, 0, 64)
+
+	for cursor.Next() {
+		var stat Statistic
+
+		if err := cursor.GetKey(&stat.ID); err != nil {
+			return nil, fmt.Errorf("get statistic id: %w", err)
+		}
+
+		if err := cursor.GetValue(&stat.Description, &stat.Printable, &stat.Value); err != nil {
+			return nil, fmt.Errorf("get statistic %d: %w", stat.ID, err)
+		}
+
+		stats = append(stats, stat)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s: %w", uri, err)
+	}
+
+	return stats, nil
+}
+
+// statisticValues looks up the values of individual statistics without
+// reading the whole cursor.
+func (s *Session) statisticValues(uri string, opts StatisticsOptions, ids ...int32) ([]int64, error) {
+	cursor, err := s.OpenCursor(uri, opts.config())
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", uri, err)
+	}
+
+	defer cursor.Close()
+
+	values := make([]int64, len(ids))
+
+	for i, id := range ids {
+		if err := cursor.SetKey(id); err != nil {
+			return nil, fmt.Errorf("set statistic id: %w", err)
+		}
+
+		if err := cursor.Search(); err != nil {
+			return nil, fmt.Errorf("search %s for statistic %d: %w", uri, id, err)
+		}
+
+		var description, printable string
+
+		if err := cursor.GetValue(&description, &printable, &values[i]); err != nil {
+			return nil, fmt.Errorf("get statistic %d: %w", id, err)
+		}
+	}
+
+	return values, nil
+}
+
+type CacheUsage struct {
+	Bytes      int64
+	DirtyBytes int64
+	MaxBytes   int64
+}
+
+func (s *Session) CacheUsage() (CacheUsage, error) {
+	values, err := s.statisticValues("statistics:", StatisticsOptions{},
+		C.WT_STAT_CONN_CACHE_BYTES_INUSE,
+		C.WT_STAT_CONN_CACHE_BYTES_DIRTY,
+		C.WT_STAT_CONN_CACHE_BYTES_MAX,
+	)
+	if err != nil {
+		return CacheUsage{}, err
+	}
+
+	return CacheUsage{Bytes: values[0], DirtyBytes: values[1], MaxBytes: values[2]}, nil
+}
+
+// EntryCount returns the number of key/value pairs in the table at uri. It
+// walks the tree, so the connection must have been opened with
+// statistics=(all).
+func (s *Session) EntryCount(uri string) (int64, error) {
+	values, err := s.statisticValues("statistics:"+uri, StatisticsOptions{Mode: StatisticsAll}, C.WT_STAT_DSRC_BTREE_ENTRIES)
+	if err != nil {
+		return 0, err
+	}
+
+	return values[0], nil
+}
@@ -0,0 +1,110 @@
+package wtgo_test
+
+import (
+	"github.com/dylrich/wtgo"
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create,cache_size=10MB,statistics=(all)", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	for _, k := range []string{"a", "b", "c"} {
+		if err := insert(env.cursor, k, k); err != nil {
+			t.Fatalf("insert %s: %s", k, err)
+		}
+	}
+
+	if err := env.cursor.Reset(); err != nil {
+		t.Fatalf("reset: %s", err)
+	}
+
+	for name, read := range map[string]func() (wtgo.Statistics, error){
+		"connection": func() (wtgo.Statistics, error) {
+			return env.session.ConnectionStatistics(wtgo.StatisticsOptions{Mode: wtgo.StatisticsFast})
+		},
+		"table": func() (wtgo.Statistics, error) {
+			return env.session.Statistics(tablename, wtgo.StatisticsOptions{Mode: wtgo.StatisticsAll})
+		},
+		"session": func() (wtgo.Statistics, error) {
+			return env.session.SessionStatistics(wtgo.StatisticsOptions{})
+		},
+	} {
+		stats, err := read()
+		if err != nil {
+			t.Fatalf("read %s statistics: %s", name, err)
+		}
+
+		if len(stats) == 0 {
+			t.Fatalf("no %s statistics", name)
+		}
+
+		for _, s := range stats {
+			if s.Description == "" {
+				t.Fatalf("%s statistic %d has no description", name, s.ID)
+			}
+
+			if got, ok := stats.ByID(s.ID); !ok || got != s {
+				t.Fatalf("%s statistic %d lookup returned %v", name, s.ID, got)
+			}
+		}
+	}
+
+	usage, err := env.session.CacheUsage()
+	if err != nil {
+		t.Fatalf("cache usage: %s", err)
+	}
+
+	if usage.MaxBytes != 10<<20 || usage.Bytes <= 0 || usage.Bytes > usage.MaxBytes {
+		t.Fatalf("unexpected cache usage %+v", usage)
+	}
+
+	n, err := env.session.EntryCount(tablename)
+	if err != nil {
+		t.Fatalf("entry count: %s", err)
+	}
+
+	if n != 3 {
+		t.Fatalf("entry count is %d, expected 3", n)
+	}
+
+	const inserts = "cursor: insert calls"
+
+	stats, err := env.session.Statistics(tablename, wtgo.StatisticsOptions{Mode: wtgo.StatisticsFast, Clear: true})
+	if err != nil {
+		t.Fatalf("read and clear table statistics: %s", err)
+	}
+
+	if s, ok := stats.ByDescription(inserts); !ok || s.Value != 3 {
+		t.Fatalf("'%s' before clear is %v, expected 3", inserts, s)
+	}
+
+	stats, err = env.session.Statistics(tablename, wtgo.StatisticsOptions{Mode: wtgo.StatisticsFast})
+	if err != nil {
+		t.Fatalf("read table statistics: %s", err)
+	}
+
+	if s, ok := stats.ByDescription(inserts); !ok || s.Value != 0 {
+		t.Fatalf("'%s' after clear is %v, expected 0", inserts, s)
+	}
+}
+
+func TestStatisticsDisabled(t *testing.T) {
+	env, err := newSessionTestEnv("create", "")
+	if err != nil {
+		t.Fatalf("new session test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if _, err := env.session.ConnectionStatistics(wtgo.StatisticsOptions{}); err == nil {
+		t.Fatalf("read statistics of a connection without statistics succeeded")
+	}
+}
@@ -0,0 +1,200 @@
+// Package statlog reads the JSON files written by a connection opened with
+// statistics_log=(json=true) into time series.
+package statlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Key identifies a series by the statistic's description, e.g. "cache:
+// bytes currently in the cache", and the uri of the data source it was
+// logged for. Connection statistics have an empty URI.
+type Key struct {
+	Name string
+	URI  string
+}
+
+type Point struct {
+	Time  time.Time
+	Value int64
+}
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+	Delta int64
+}
+
+type Series struct {
+	Key    Key
+	Points []Point
+}
+
+// Deltas returns the change of the series between consecutive points.
+func (s *Series) Deltas() []Interval {
+	if len(s.Points) < 2 {
+		return nil
+	}
+
+	deltas := make([]Interval, 0, len(s.Points)-1)
+
+	for i := 1; i < len(s.Points); i++ {
+		prev, cur := s.Points[i-1], s.Points[i]
+		deltas = append(deltas, Interval{Start: prev.Time, End: cur.Time, Delta: cur.Value - prev.Value})
+	}
+
+	return deltas
+}
+
+type Log struct {
+	Series map[Key]*Series
+}
+
+func (l *Log) Get(uri, name string) (*Series, bool) {
+	s, ok := l.Series[Key{Name: name, URI: uri}]
+	return s, ok
+}
+
+// Keys returns the keys of every series, sorted by uri and then name.
+func (l *Log) Keys() []Key {
+	keys := make([]Key, 0, len(l.Series))
+
+	for k := range l.Series {
+		keys = append(keys, k)
+	}
+
+	slices.SortFunc(keys, func(a, b Key) int {
+		if c := strings.Compare(a.URI, b.URI); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return keys
+}
+
+// Read decodes a statistics log, one JSON record per line.
+func Read(r io.Reader) (*Log, error) {
+	l := &Log{Series: make(map[Key]*Series)}
+
+	if err := l.read(r); err != nil {
+		return nil, err
+	}
+
+	l.sort()
+
+	return l, nil
+}
+
+// ReadFiles reads several statistics log files, such as the hourly
+// WiredTigerStat.* files, into one log.
+func ReadFiles(names ...string) (*Log, error) {
+	l := &Log{Series: make(map[Key]*Series)}
+
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.read(f)
+		f.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	l.sort()
+
+	return l, nil
+}
+
+type record struct {
+	LocalTime        string                                       `json:"localTime"`
+	WiredTiger       map[string]map[string]json.Number            `json:"wiredTiger"`
+	WiredTigerTables map[string]map[string]map[string]json.Number `json:"wiredTigerTables"`
+}
+
+func (l *Log) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		if err := l.decode(data); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (l *Log) decode(data []byte) error {
+	var rec record
+
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, rec.LocalTime)
+	if err != nil {
+		return fmt.Errorf("parse localTime: %w", err)
+	}
+
+	if err := l.add(t, "", rec.WiredTiger); err != nil {
+		return err
+	}
+
+	for uri, groups := range rec.WiredTigerTables {
+		if err := l.add(t, uri, groups); err != nil {
+			return fmt.Errorf("%s: %w", uri, err)
+		}
+	}
+
+	return nil
+}
+
+// add records the statistics of one source. WiredTiger logs the description
+// "cache: bytes read into cache" as {"cache":{"bytes read into cache":n}}.
+func (l *Log) add(t time.Time, uri string, groups map[string]map[string]json.Number) error {
+	for group, values := range groups {
+		for name, v := range values {
+			n, err := v.Int64()
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", group, name, err)
+			}
+
+			key := Key{Name: group + ": " + name, URI: uri}
+
+			s, ok := l.Series[key]
+			if !ok {
+				s = &Series{Key: key}
+				l.Series[key] = s
+			}
+
+			s.Points = append(s.Points, Point{Time: t, Value: n})
+		}
+	}
+
+	return nil
+}
+
+func (l *Log) sort() {
+	for _, s := range l.Series {
+		slices.SortStableFunc(s.Points, func(a, b Point) int { return a.Time.Compare(b.Time) })
+	}
+}
@@ -0,0 +1,87 @@
+package statlog_test
+
+import (
+	"github.com/dylrich/wtgo/statlog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadFiles(t *testing.T) {
+	l, err := statlog.ReadFiles(filepath.Join("testdata", "WiredTigerStat.json"))
+	if err != nil {
+		t.Fatalf("read files: %s", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	keys := l.Keys()
+
+	want := []statlog.Key{
+		{Name: "cache: bytes currently in the cache"},
+		{Name: "cache: pages evicted by application threads"},
+		{Name: "cache: tracked dirty bytes in the cache"},
+		{Name: "transaction: transaction checkpoint most recent time (msecs)"},
+		{Name: "transaction: transaction checkpoint total time (msecs)"},
+		{Name: "btree: number of key/value pairs", URI: "table:orders"},
+		{Name: "cursor: insert calls", URI: "table:orders"},
+	}
+
+	if diff := cmp.Diff(want, keys); diff != "" {
+		t.Fatalf("keys don't match (-want +got):\n%s", diff)
+	}
+
+	inserts, ok := l.Get("table:orders", "cursor: insert calls")
+	if !ok {
+		t.Fatalf("no insert calls series")
+	}
+
+	wantPoints := []statlog.Point{{Time: at(0), Value: 0}, {Time: at(10), Value: 250}, {Time: at(20), Value: 300}}
+	if diff := cmp.Diff(wantPoints, inserts.Points); diff != "" {
+		t.Fatalf("points don't match (-want +got):\n%s", diff)
+	}
+
+	wantDeltas := []statlog.Interval{{Start: at(0), End: at(10), Delta: 250}, {Start: at(10), End: at(20), Delta: 50}}
+	if diff := cmp.Diff(wantDeltas, inserts.Deltas()); diff != "" {
+		t.Fatalf("deltas don't match (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadOrdersPoints(t *testing.T) {
+	log := `{"localTime":"2024-01-01T00:00:10.000Z","wiredTiger":{"cache":{"bytes currently in the cache":2}}}
+{"localTime":"2024-01-01T00:00:00.000Z","wiredTiger":{"cache":{"bytes currently in the cache":1}}}`
+
+	l, err := statlog.Read(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("read: %s", err)
+	}
+
+	s, ok := l.Get("", "cache: bytes currently in the cache")
+	if !ok {
+		t.Fatalf("no cache series")
+	}
+
+	if len(s.Points) != 2 || s.Points[0].Value != 1 || s.Points[1].Value != 2 {
+		t.Fatalf("points are not ordered by time: %v", s.Points)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	cases := map[string]string{
+		"invalid json": `{"localTime":`,
+		"invalid time": `{"localTime":"yesterday","wiredTiger":{}}`,
+		"non integer":  `{"localTime":"2024-01-01T00:00:00.000Z","wiredTiger":{"cache":{"bytes currently in the cache":1.5}}}`,
+	}
+
+	for name, log := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := statlog.Read(strings.NewReader(log)); err == nil || !strings.Contains(err.Error(), "line 1") {
+				t.Fatalf("read returned err '%v', expected an error on line 1", err)
+			}
+		})
+	}
+}
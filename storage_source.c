@@ -0,0 +1,80 @@
+#include "wiredtiger.h"
+#include "_cgo_export.h"
+#include <errno.h>
+#include <stdlib.h>
+
+WT_FILE_SYSTEM *wtgo_file_system_new(uintptr_t handle);
+uintptr_t wtgo_file_system_handle(WT_FILE_SYSTEM *file_system);
+
+typedef struct {
+	WT_STORAGE_SOURCE iface;
+	uintptr_t handle;
+	uint32_t refs;
+} wtgo_storage_source;
+
+static int wtgo_storage_source_customize_file_system(WT_STORAGE_SOURCE *storage_source, WT_SESSION *session, const char *bucket_name, const char *auth_token, const char *config, WT_FILE_SYSTEM **file_systemp) {
+	uintptr_t handle;
+
+	int ret = wtgoStorageSourceBucket(((wtgo_storage_source *)storage_source)->handle, (char *)bucket_name, (char *)auth_token, (char *)config, &handle);
+	if (ret != 0) {
+		return ret;
+	}
+
+	WT_FILE_SYSTEM *fs = wtgo_file_system_new(handle);
+	if (fs == NULL) {
+		wtgoFileSystemTerminate(handle);
+		return ENOMEM;
+	}
+
+	*file_systemp = fs;
+
+	return 0;
+}
+
+static int wtgo_storage_source_add_reference(WT_STORAGE_SOURCE *storage_source) {
+	__atomic_add_fetch(&((wtgo_storage_source *)storage_source)->refs, 1, __ATOMIC_SEQ_CST);
+	return 0;
+}
+
+static int wtgo_storage_source_flush(WT_STORAGE_SOURCE *storage_source, WT_SESSION *session, WT_FILE_SYSTEM *file_system, const char *source, const char *object, const char *config) {
+	return wtgoStorageSourceFlush(wtgo_file_system_handle(file_system), (char *)source, (char *)object, 0);
+}
+
+static int wtgo_storage_source_flush_finish(WT_STORAGE_SOURCE *storage_source, WT_SESSION *session, WT_FILE_SYSTEM *file_system, const char *source, const char *object, const char *config) {
+	return wtgoStorageSourceFlush(wtgo_file_system_handle(file_system), (char *)source, (char *)object, 1);
+}
+
+static int wtgo_storage_source_terminate(WT_STORAGE_SOURCE *storage_source, WT_SESSION *session) {
+	wtgo_storage_source *s = (wtgo_storage_source *)storage_source;
+
+	if (__atomic_sub_fetch(&s->refs, 1, __ATOMIC_SEQ_CST) > 0) {
+		return 0;
+	}
+
+	wtgoStorageSourceTerminate(s->handle);
+	free(s);
+
+	return 0;
+}
+
+int wiredtiger_connection_add_storage_source(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config) {
+	wtgo_storage_source *s = calloc(1, sizeof(wtgo_storage_source));
+	if (s == NULL) {
+		return ENOMEM;
+	}
+
+	s->iface.ss_customize_file_system = wtgo_storage_source_customize_file_system;
+	s->iface.ss_add_reference = wtgo_storage_source_add_reference;
+	s->iface.ss_flush = wtgo_storage_source_flush;
+	s->iface.ss_flush_finish = wtgo_storage_source_flush_finish;
+	s->iface.terminate = wtgo_storage_source_terminate;
+	s->handle = handle;
+	s->refs = 1;
+
+	int ret = connection->add_storage_source(connection, name, &s->iface, config);
+	if (ret != 0) {
+		free(s);
+	}
+
+	return ret;
+}
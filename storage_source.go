@@ -0,0 +1,257 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_add_storage_source(WT_CONNECTION *connection, const char *name, uintptr_t handle, const char *config);
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/cgo"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// StorageSource is the shared storage of tiered tables, e.g. an object
+// store. It must be registered with AddStorageSource in
+// OpenOptions.EarlyLoad when the connection is opened with
+// "tiered_storage=(name=<name>,bucket=<bucket>,...)".
+type StorageSource interface {
+	Bucket(name, authToken, config string) (Bucket, error)
+}
+
+// Bucket holds the objects flushed from the local database. WiredTiger reads
+// and removes objects through its FileSystem methods, which see object
+// names without a directory.
+type Bucket interface {
+	FileSystem
+
+	// Flush copies the local file source into the bucket as object.
+	Flush(source, object string) error
+
+	// FlushFinish is called once the flush of object has been recorded
+	// durably by WiredTiger.
+	FlushFinish(source, object string) error
+}
+
+func (conn *Connection) AddStorageSource(name string, s StorageSource) error {
+	if s == nil {
+		return fmt.Errorf("storage source %s is nil", name)
+	}
+
+	h := cgo.NewHandle(s)
+
+	namecstr := C.CString(name)
+	defer C.free(unsafe.Pointer(namecstr))
+
+	if code := int(C.wiredtiger_connection_add_storage_source(conn.wtc, namecstr, C.uintptr_t(h), nil)); code != 0 {
+		h.Delete()
+		return ErrorCode(code)
+	}
+
+	return nil
+}
+
+// FlushTier checkpoints the database and copies the objects of tiered
+// tables to their buckets. config holds flush_tier settings such as
+// "force=true".
+func (s *Session) FlushTier(config string) error {
+	c := "flush_tier=(enabled=true"
+	if config != "" {
+		c += "," + config
+	}
+
+	return s.Checkpoint(c + ")")
+}
+
+//export wtgoStorageSourceBucket
+func wtgoStorageSourceBucket(handle C.uintptr_t, name, authToken, config *C.char, bucketHandle *C.uintptr_t) C.int {
+	s := cgo.Handle(handle).Value().(StorageSource)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		b, err := s.Bucket(C.GoString(name), C.GoString(authToken), C.GoString(config))
+		if err != nil {
+			return err
+		}
+
+		*bucketHandle = C.uintptr_t(cgo.NewHandle(b))
+
+		return nil
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoStorageSourceFlush
+func wtgoStorageSourceFlush(bucketHandle C.uintptr_t, source, object *C.char, finish C.int) C.int {
+	b := cgo.Handle(bucketHandle).Value().(Bucket)
+
+	err := func() (err error) {
+		defer recoverCallback(&err)
+
+		if finish != 0 {
+			return b.FlushFinish(C.GoString(source), C.GoString(object))
+		}
+
+		return b.Flush(C.GoString(source), C.GoString(object))
+	}()
+
+	return C.int(callbackCode(err))
+}
+
+//export wtgoStorageSourceTerminate
+func wtgoStorageSourceTerminate(handle C.uintptr_t) {
+	cgo.Handle(handle).Delete()
+}
+
+// LocalStorageSource stands in for an object store: each bucket is a
+// directory under Root, which must exist before the connection is opened.
+type LocalStorageSource struct {
+	Root string
+}
+
+func (l LocalStorageSource) Bucket(name, authToken, config string) (Bucket, error) {
+	dir := filepath.Join(l.Root, name)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("bucket %s: %w", name, err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("bucket %s: %s is not a directory", name, dir)
+	}
+
+	return localBucket{dir: dir}, nil
+}
+
+type localBucket struct {
+	dir string
+}
+
+func (b localBucket) path(name string) string {
+	return filepath.Join(b.dir, filepath.Base(name))
+}
+
+func (b localBucket) List(dir, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (b localBucket) Exists(name string) (bool, error) {
+	_, err := os.Stat(b.path(name))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Open gives read-only access to an object; objects are only written by
+// Flush.
+func (b localBucket) Open(name string, typ FileType, flags FileOpenFlags) (File, error) {
+	f, err := os.Open(b.path(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return osFile{File: f}, nil
+}
+
+func (b localBucket) Remove(name string) error {
+	return os.Remove(b.path(name))
+}
+
+func (b localBucket) Rename(from, to string) error {
+	return os.Rename(b.path(from), b.path(to))
+}
+
+func (b localBucket) Size(name string) (int64, error) {
+	info, err := os.Stat(b.path(name))
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// Flush copies through a temporary file so that a partially copied object
+// is never visible under its final name.
+func (b localBucket) Flush(source, object string) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(b.dir, ".flush-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("copy %s: %w", source, err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), b.path(object))
+}
+
+func (b localBucket) FlushFinish(source, object string) error {
+	_, err := os.Stat(b.path(object))
+	return err
+}
+
+type osFile struct {
+	*os.File
+}
+
+func (f osFile) Size() (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+func (f osFile) Lock(lock bool) error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package wtgo_test
+
+import (
+	"fmt"
+	"github.com/dylrich/wtgo"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type countingStorageSource struct {
+	wtgo.StorageSource
+	flushed atomic.Int64
+}
+
+func (c *countingStorageSource) Bucket(name, authToken, config string) (wtgo.Bucket, error) {
+	b, err := c.StorageSource.Bucket(name, authToken, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingBucket{Bucket: b, flushed: &c.flushed}, nil
+}
+
+type countingBucket struct {
+	wtgo.Bucket
+	flushed *atomic.Int64
+}
+
+func (c *countingBucket) Flush(source, object string) error {
+	c.flushed.Add(1)
+	return c.Bucket.Flush(source, object)
+}
+
+func TestTieredStorage(t *testing.T) {
+	if testFileSystem != "" {
+		t.Skip("flushes local files from disk")
+	}
+
+	dir := t.TempDir()
+	root := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(root, "bucket1"), 0o755); err != nil {
+		t.Fatalf("make bucket: %s", err)
+	}
+
+	source := &countingStorageSource{StorageSource: wtgo.LocalStorageSource{Root: root}}
+
+	opts := wtgo.OpenOptions{
+		EarlyLoad: []func(*wtgo.Connection) error{
+			func(conn *wtgo.Connection) error { return conn.AddStorageSource("golocal", source) },
+		},
+	}
+
+	config := "create,tiered_storage=(name=golocal,bucket=bucket1,bucket_prefix=pfx-,local_retention=0)"
+
+	conn, err := wtgo.OpenWithOptions(dir, config, opts)
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	tablename := "table:tiered"
+
+	if err := session.Create(tablename, "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	cursor, err := session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	const rows = 500
+
+	for i := 0; i < rows; i++ {
+		if err := insert(cursor, fmt.Sprintf("key%04d", i), fmt.Sprintf("value%04d", i)); err != nil {
+			t.Fatalf("insert %d: %s", i, err)
+		}
+	}
+
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("close cursor: %s", err)
+	}
+
+	if err := session.FlushTier(""); err != nil {
+		t.Fatalf("flush tier: %s", err)
+	}
+
+	if source.flushed.Load() == 0 {
+		t.Fatalf("flush tier did not flush any object")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "bucket1"))
+	if err != nil {
+		t.Fatalf("read bucket: %s", err)
+	}
+
+	var objects []string
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "pfx-tiered") {
+			objects = append(objects, e.Name())
+		}
+	}
+
+	if len(objects) == 0 {
+		t.Fatalf("bucket has no objects of %s: %v", tablename, entries)
+	}
+
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close: %s", err)
+	}
+
+	conn, err = wtgo.OpenWithOptions(dir, config, opts)
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	session, err = conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session after reopen: %s", err)
+	}
+
+	cursor, err = session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor after reopen: %s", err)
+	}
+
+	var n int
+
+	for ; cursor.Next(); n++ {
+		r, err := getResult[string, string](cursor)
+		if err != nil {
+			t.Fatalf("get result: %s", err)
+		}
+
+		if want := fmt.Sprintf("value%04d", n); r.Value != want {
+			t.Fatalf("got value %s for %s, wanted %s", r.Value, r.Key, want)
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	if n != rows {
+		t.Fatalf("read %d rows after reopen, wanted %d", n, rows)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+ * A WiredTiger extension used by the tests: it registers a collator named
+ * "reverse" that orders keys by descending byte value. Setting fail=true in
+ * its config makes initialization fail.
+ */
+#include "wiredtiger.h"
+#include <errno.h>
+#include <string.h>
+
+static int reverse_compare(WT_COLLATOR *collator, WT_SESSION *session, const WT_ITEM *k1, const WT_ITEM *k2, int *cmp) {
+	size_t n = k1->size < k2->size ? k1->size : k2->size;
+
+	int r = memcmp(k1->data, k2->data, n);
+	if (r == 0) {
+		r = k1->size < k2->size ? -1 : k1->size > k2->size ? 1 : 0;
+	}
+
+	*cmp = -r;
+
+	return 0;
+}
+
+static WT_COLLATOR reverse_collator = {reverse_compare, NULL, NULL};
+
+int wtgo_test_extension_init(WT_CONNECTION *connection, WT_CONFIG_ARG *config) {
+	WT_EXTENSION_API *api = connection->get_extension_api(connection);
+	WT_CONFIG_ITEM fail;
+
+	if (api->config_get(api, NULL, config, "fail", &fail) == 0 && fail.val != 0) {
+		return EINVAL;
+	}
+
+	return connection->add_collator(connection, "reverse", &reverse_collator, NULL);
+}
@@ -0,0 +1,141 @@
+package wtgo
+
+/*
+#include "wiredtiger.h"
+#include <stdlib.h>
+
+int wiredtiger_connection_set_timestamp(WT_CONNECTION *connection, const char *config) {
+	return connection->set_timestamp(connection, config);
+}
+
+int wiredtiger_connection_query_timestamp(WT_CONNECTION *connection, char *hex_timestamp, const char *config) {
+	return connection->query_timestamp(connection, hex_timestamp, config);
+}
+
+int wiredtiger_connection_rollback_to_stable(WT_CONNECTION *connection, const char *config) {
+	return connection->rollback_to_stable(connection, config);
+}
+*/
+import (
+	"C"
+)
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+type ConnectionTimestampType string
+
+const (
+	ConnectionTimestampTypeOldest ConnectionTimestampType = "oldest_timestamp"
+	ConnectionTimestampTypeStable ConnectionTimestampType = "stable_timestamp"
+
+	// ConnectionTimestampTypeAllDurable is the largest timestamp at which
+	// every earlier commit is durable.
+	ConnectionTimestampTypeAllDurable ConnectionTimestampType = "all_durable"
+
+	ConnectionTimestampTypeOldestReader   ConnectionTimestampType = "oldest_reader"
+	ConnectionTimestampTypePinned         ConnectionTimestampType = "pinned"
+	ConnectionTimestampTypeLastCheckpoint ConnectionTimestampType = "last_checkpoint"
+	ConnectionTimestampTypeRecovery       ConnectionTimestampType = "recovery"
+)
+
+// ConnectionTimestamps are set together with SetTimestamps. Zero fields
+// are left unchanged.
+type ConnectionTimestamps struct {
+	Oldest  uint64
+	Stable  uint64
+	Durable uint64
+
+	// Force allows the oldest and stable timestamps to move backwards.
+	Force bool
+}
+
+func (t ConnectionTimestamps) config() string {
+	var parts []string
+
+	if t.Oldest != 0 {
+		parts = append(parts, "oldest_timestamp="+strconv.FormatUint(t.Oldest, 16))
+	}
+
+	if t.Stable != 0 {
+		parts = append(parts, "stable_timestamp="+strconv.FormatUint(t.Stable, 16))
+	}
+
+	if t.Durable != 0 {
+		parts = append(parts, "durable_timestamp="+strconv.FormatUint(t.Durable, 16))
+	}
+
+	if t.Force {
+		parts = append(parts, "force=true")
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (conn *Connection) SetTimestamps(t ConnectionTimestamps) error {
+	config := t.config()
+	if config == "" {
+		return nil
+	}
+
+	configcstr := C.CString(config)
+	defer C.free(unsafe.Pointer(configcstr))
+
+	if code := int(C.wiredtiger_connection_set_timestamp(conn.wtc, configcstr)); code != 0 {
+		return fmt.Errorf("set timestamps %s: %w", config, ErrorCode(code))
+	}
+
+	return nil
+}
+
+func (conn *Connection) SetOldestTimestamp(ts uint64) error {
+	return conn.SetTimestamps(ConnectionTimestamps{Oldest: ts})
+}
+
+func (conn *Connection) SetStableTimestamp(ts uint64) error {
+	return conn.SetTimestamps(ConnectionTimestamps{Stable: ts})
+}
+
+func (conn *Connection) SetDurableTimestamp(ts uint64) error {
+	return conn.SetTimestamps(ConnectionTimestamps{Durable: ts})
+}
+
+// QueryTimestamp returns a global timestamp, or 0 if it has not been set.
+func (conn *Connection) QueryTimestamp(t ConnectionTimestampType) (uint64, error) {
+	configcstr := C.CString("get=" + string(t))
+	defer C.free(unsafe.Pointer(configcstr))
+
+	var tsc [17]C.char
+
+	if code := int(C.wiredtiger_connection_query_timestamp(conn.wtc, &tsc[0], configcstr)); code != 0 {
+		return 0, fmt.Errorf("query %s: %w", t, ErrorCode(code))
+	}
+
+	ts, err := strconv.ParseUint(C.GoString(&tsc[0]), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse hex timestamp: %w", err)
+	}
+
+	return ts, nil
+}
+
+// RollbackToStable discards every update newer than the stable timestamp.
+// No transaction may be active on the connection.
+func (conn *Connection) RollbackToStable(config string) error {
+	var configcstr *C.char
+
+	if config != "" {
+		configcstr = C.CString(config)
+		defer C.free(unsafe.Pointer(configcstr))
+	}
+
+	if code := int(C.wiredtiger_connection_rollback_to_stable(conn.wtc, configcstr)); code != 0 {
+		return ErrorCode(code)
+	}
+
+	return nil
+}
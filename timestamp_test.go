@@ -0,0 +1,212 @@
+package wtgo_test
+
+import (
+	"errors"
+	"github.com/dylrich/wtgo"
+	"testing"
+)
+
+func commitAt(session *wtgo.Session, cursor *wtgo.Cursor, ts uint64, k, v string) error {
+	if err := session.BeginTransaction(""); err != nil {
+		return err
+	}
+
+	if err := insert(cursor, k, v); err != nil {
+		session.RollbackTransaction("")
+		return err
+	}
+
+	if err := session.TimestampTransactionUint(wtgo.TransactionTimestampTypeCommit, ts); err != nil {
+		session.RollbackTransaction("")
+		return err
+	}
+
+	return session.CommitTransaction("")
+}
+
+func TestConnectionTimestamps(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if ts, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeStable); err != nil || ts != 0 {
+		t.Fatalf("query unset stable timestamp returned %d, %v", ts, err)
+	}
+
+	for _, ts := range []uint64{10, 20} {
+		if err := commitAt(env.session, env.cursor, ts, "a", "v"); err != nil {
+			t.Fatalf("commit at %d: %s", ts, err)
+		}
+	}
+
+	if err := env.conn.SetTimestamps(wtgo.ConnectionTimestamps{Oldest: 5, Stable: 10}); err != nil {
+		t.Fatalf("set timestamps: %s", err)
+	}
+
+	reader, err := env.conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open reader session: %s", err)
+	}
+
+	if err := reader.BeginTransaction("read_timestamp=f"); err != nil {
+		t.Fatalf("begin read transaction: %s", err)
+	}
+
+	for typ, want := range map[wtgo.ConnectionTimestampType]uint64{
+		wtgo.ConnectionTimestampTypeOldest:       5,
+		wtgo.ConnectionTimestampTypeStable:       10,
+		wtgo.ConnectionTimestampTypeAllDurable:   20,
+		wtgo.ConnectionTimestampTypeOldestReader: 15,
+		wtgo.ConnectionTimestampTypePinned:       5,
+	} {
+		ts, err := env.conn.QueryTimestamp(typ)
+		if err != nil {
+			t.Fatalf("query %s: %s", typ, err)
+		}
+
+		if ts != want {
+			t.Fatalf("%s is %d, expected %d", typ, ts, want)
+		}
+	}
+
+	if err := reader.RollbackTransaction(""); err != nil {
+		t.Fatalf("rollback read transaction: %s", err)
+	}
+
+	if err := env.conn.SetOldestTimestamp(12); err == nil {
+		t.Fatalf("moving oldest past stable succeeded")
+	}
+
+	if err := env.conn.SetStableTimestamp(15); err != nil {
+		t.Fatalf("set stable timestamp: %s", err)
+	}
+
+	if err := env.conn.SetTimestamps(wtgo.ConnectionTimestamps{Stable: 12, Force: true}); err != nil {
+		t.Fatalf("force stable backwards: %s", err)
+	}
+
+	if ts, err := env.conn.QueryTimestamp(wtgo.ConnectionTimestampTypeStable); err != nil || ts != 12 {
+		t.Fatalf("query forced stable timestamp returned %d, %v", ts, err)
+	}
+}
+
+func TestRollbackToStable(t *testing.T) {
+	tablename := "table:test-table"
+
+	dir := t.TempDir()
+
+	conn, err := openTestConnection(dir, "create", wtgo.OpenOptions{})
+	if err != nil {
+		t.Fatalf("open: %s", err)
+	}
+
+	session, err := conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session: %s", err)
+	}
+
+	if err := session.Create(tablename, "key_format=S,value_format=S"); err != nil {
+		t.Fatalf("create: %s", err)
+	}
+
+	cursor, err := session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor: %s", err)
+	}
+
+	if err := commitAt(session, cursor, 10, "a", "stable"); err != nil {
+		t.Fatalf("commit at 10: %s", err)
+	}
+
+	if err := commitAt(session, cursor, 20, "a", "unstable"); err != nil {
+		t.Fatalf("commit at 20: %s", err)
+	}
+
+	if err := conn.SetTimestamps(wtgo.ConnectionTimestamps{Oldest: 1, Stable: 10}); err != nil {
+		t.Fatalf("set timestamps: %s", err)
+	}
+
+	if err := session.Checkpoint(""); err != nil {
+		t.Fatalf("checkpoint: %s", err)
+	}
+
+	if err := conn.Close(""); err != nil {
+		t.Fatalf("close: %s", err)
+	}
+
+	conn, err = openTestConnection(dir, "", wtgo.OpenOptions{})
+	if err != nil {
+		t.Fatalf("reopen: %s", err)
+	}
+
+	t.Cleanup(func() { conn.Close("") })
+
+	for typ, want := range map[wtgo.ConnectionTimestampType]uint64{
+		wtgo.ConnectionTimestampTypeStable:   10,
+		wtgo.ConnectionTimestampTypeRecovery: 10,
+	} {
+		if ts, err := conn.QueryTimestamp(typ); err != nil || ts != want {
+			t.Fatalf("query %s after restart returned %d, %v, expected %d", typ, ts, err, want)
+		}
+	}
+
+	session, err = conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open session after restart: %s", err)
+	}
+
+	cursor, err = session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor after restart: %s", err)
+	}
+
+	r, err := searchKey[string, string](cursor, "a")
+	if err != nil {
+		t.Fatalf("search after restart: %s", err)
+	}
+
+	if r.Value != "stable" {
+		t.Fatalf("value after restart is '%s', expected 'stable'", r.Value)
+	}
+
+	if err := commitAt(session, cursor, 30, "a", "unstable"); err != nil {
+		t.Fatalf("commit at 30: %s", err)
+	}
+
+	if err := commitAt(session, cursor, 30, "b", "unstable"); err != nil {
+		t.Fatalf("commit at 30: %s", err)
+	}
+
+	// Rollback to stable fails while file cursors are open.
+	if err := cursor.Close(); err != nil {
+		t.Fatalf("close cursor: %s", err)
+	}
+
+	if err := conn.RollbackToStable(""); err != nil {
+		t.Fatalf("rollback to stable: %s", err)
+	}
+
+	cursor, err = session.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open cursor after rollback: %s", err)
+	}
+
+	r, err = searchKey[string, string](cursor, "a")
+	if err != nil {
+		t.Fatalf("search after rollback: %s", err)
+	}
+
+	if r.Value != "stable" {
+		t.Fatalf("value after rollback is '%s', expected 'stable'", r.Value)
+	}
+
+	if _, err := searchKey[string, string](cursor, "b"); !errors.Is(err, wtgo.ErrNotFound) {
+		t.Fatalf("search for key written after stable returned err '%v', expected not found", err)
+	}
+}
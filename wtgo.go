@@ -186,7 +186,14 @@ int wiredtiger_cursor_reset(WT_CURSOR *cursor) {
 	return cursor->reset(cursor);
 }
 
-int wiredtiger_cursor_reserve(WT_CURSOR *cursor) {
+int wiredtiger_cursor_reserve(WT_CURSOR *cursor, const void *packed_key, size_t key_size) {
+	if (key_size != 0) {
+		WT_ITEM key;
+		key.data = packed_key;
+		key.size = key_size;
+		cursor->set_key(cursor, &key);
+	}
+
 	return cursor->reserve(cursor);
 }
 
@@ -214,7 +221,14 @@ int wiredtiger_cursor_largest_key(WT_CURSOR *cursor) {
 	return cursor->largest_key(cursor);
 }
 
-int wiredtiger_cursor_bound(WT_CURSOR *cursor, const char *config) {
+int wiredtiger_cursor_bound(WT_CURSOR *cursor, const char *config, const void *packed_key, size_t key_size) {
+	if (key_size != 0) {
+		WT_ITEM key;
+		key.data = packed_key;
+		key.size = key_size;
+		cursor->set_key(cursor, &key);
+	}
+
 	return cursor->bound(cursor, config);
 }
 
@@ -247,9 +261,15 @@ import (
 )
 
 import (
+	"errors"
 	"fmt"
+	"github.com/dylrich/wtgo/internal/wtconfig"
 	"github.com/dylrich/wtgo/internal/wtformat"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -279,15 +299,115 @@ const (
 
 type Connection struct {
 	wtc *C.WT_CONNECTION
+
+	// Event handlers are owned by Go and freed once WiredTiger is done
+	// with them.
+	eventHandler    *C.WT_EVENT_HANDLER
+	mu              sync.Mutex
+	sessionHandlers map[*C.WT_EVENT_HANDLER]struct{}
+
+	// Background workers such as samplers are stopped before the
+	// connection closes.
+	workers map[worker]struct{}
+}
+
+type worker interface {
+	Stop() error
+}
+
+func (conn *Connection) addWorker(w worker) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.workers == nil {
+		conn.workers = make(map[worker]struct{})
+	}
+
+	conn.workers[w] = struct{}{}
+}
+
+func (conn *Connection) removeWorker(w worker) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	delete(conn.workers, w)
+}
+
+type OpenOptions struct {
+	// EarlyLoad functions run inside wiredtiger_open, before recovery and
+	// before any object is opened. Encryptors named by the connection's
+	// encryption= config, file systems and storage sources must be
+	// registered here. The Connection passed in is only valid for the
+	// duration of the call.
+	EarlyLoad []func(conn *Connection) error
+
+	// FileSystem replaces the operating system's file system for every
+	// file of the database, including the home directory's lock file.
+	FileSystem FileSystem
+
+	// EventHandler receives the events of the connection and of sessions
+	// opened without their own handler.
+	EventHandler EventHandler
+
+	Extensions []Extension
 }
 
 func Open(home, config string) (*Connection, error) {
+	return OpenWithOptions(home, config, OpenOptions{})
+}
+
+func OpenWithOptions(home, config string, opts OpenOptions) (*Connection, error) {
+	config, err := extensionsConfig(config, opts.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	fns := opts.EarlyLoad
+
+	if opts.FileSystem != nil {
+		fns = append([]func(*Connection) error{func(conn *Connection) error { return conn.setFileSystem(opts.FileSystem) }}, fns...)
+	}
+
+	var early *earlyLoad
+
+	if len(fns) > 0 {
+		early = newEarlyLoad(fns)
+		defer early.close()
+
+		c, err := early.config(config)
+		if err != nil {
+			return nil, err
+		}
+
+		config = c
+	}
+
+	eh, err := newEventHandler(opts.EventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := open(home, config, eh)
+	if err != nil {
+		freeEventHandler(eh)
+
+		if early != nil && early.err != nil {
+			return nil, fmt.Errorf("early load: %w", early.err)
+		}
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func open(home, config string, eh *C.WT_EVENT_HANDLER) (*Connection, error) {
 	var wtc *C.WT_CONNECTION
 
 	homecstr := C.CString(home)
 	configcstr := C.CString(config)
 
-	code := int(C.wiredtiger_open(homecstr, nil, configcstr, &wtc))
+	code := int(C.wiredtiger_open(homecstr, eh, configcstr, &wtc))
 
 	C.free(unsafe.Pointer(homecstr))
 	C.free(unsafe.Pointer(configcstr))
@@ -297,7 +417,8 @@ func Open(home, config string) (*Connection, error) {
 	}
 
 	conn := &Connection{
-		wtc: wtc,
+		wtc:          wtc,
+		eventHandler: eh,
 	}
 
 	return conn, nil
@@ -305,9 +426,25 @@ func Open(home, config string) (*Connection, error) {
 
 type Session struct {
 	wtsession *C.WT_SESSION
+
+	conn         *Connection
+	eventHandler *C.WT_EVENT_HANDLER
+
+	txn     bool
+	cursors *cursorCache
+}
+
+type SessionOptions struct {
+	// EventHandler receives the events of this session instead of the
+	// connection's handler.
+	EventHandler EventHandler
 }
 
 func (conn *Connection) OpenSession(config string) (*Session, error) {
+	return conn.OpenSessionWithOptions(config, SessionOptions{})
+}
+
+func (conn *Connection) OpenSessionWithOptions(config string, opts SessionOptions) (*Session, error) {
 	var wts *C.WT_SESSION
 
 	var configcstr *C.char
@@ -317,12 +454,29 @@ func (conn *Connection) OpenSession(config string) (*Session, error) {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
-	if code := int(C.wiredtiger_connection_open_session(conn.wtc, nil, configcstr, &wts)); code != 0 {
+	eh, err := newEventHandler(opts.EventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	if code := int(C.wiredtiger_connection_open_session(conn.wtc, eh, configcstr, &wts)); code != 0 {
+		freeEventHandler(eh)
 		return nil, ErrorCode(code)
 	}
 
+	if eh != nil {
+		conn.mu.Lock()
+		if conn.sessionHandlers == nil {
+			conn.sessionHandlers = make(map[*C.WT_EVENT_HANDLER]struct{})
+		}
+		conn.sessionHandlers[eh] = struct{}{}
+		conn.mu.Unlock()
+	}
+
 	s := &Session{
-		wtsession: wts,
+		wtsession:    wts,
+		conn:         conn,
+		eventHandler: eh,
 	}
 	return s, nil
 }
@@ -335,7 +489,32 @@ func (conn *Connection) Close(config string) error {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
-	if code := int(C.wiredtiger_connection_close(conn.wtc, configcstr)); code != 0 {
+	conn.mu.Lock()
+	workers := make([]worker, 0, len(conn.workers))
+	for w := range conn.workers {
+		workers = append(workers, w)
+	}
+	conn.mu.Unlock()
+
+	for _, w := range workers {
+		w.Stop()
+	}
+
+	code := int(C.wiredtiger_connection_close(conn.wtc, configcstr))
+
+	// WiredTiger releases the connection even when close fails, and may
+	// report events until it returns.
+	conn.mu.Lock()
+	for eh := range conn.sessionHandlers {
+		freeEventHandler(eh)
+	}
+	conn.sessionHandlers = nil
+	conn.mu.Unlock()
+
+	freeEventHandler(conn.eventHandler)
+	conn.eventHandler = nil
+
+	if code != 0 {
 		return ErrorCode(code)
 	}
 
@@ -354,6 +533,8 @@ func (s *Session) BeginTransaction(config string) error {
 		return ErrorCode(code)
 	}
 
+	s.txn = true
+
 	return nil
 }
 
@@ -365,6 +546,8 @@ func (s *Session) CommitTransaction(config string) error {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
+	s.txn = false
+
 	if code := int(C.wiredtiger_session_commit_transaction(s.wtsession, configcstr)); code != 0 {
 		return ErrorCode(code)
 	}
@@ -395,11 +578,30 @@ func (s *Session) Close(config string) error {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
-	if code := int(C.wiredtiger_session_close(s.wtsession, configcstr)); code != 0 {
-		return ErrorCode(code)
+	var errs []error
+
+	if err := s.closeCachedCursors(); err != nil {
+		errs = append(errs, fmt.Errorf("close cached cursors: %w", err))
 	}
 
-	return nil
+	code := int(C.wiredtiger_session_close(s.wtsession, configcstr))
+
+	if s.eventHandler != nil {
+		s.conn.mu.Lock()
+		if _, ok := s.conn.sessionHandlers[s.eventHandler]; ok {
+			delete(s.conn.sessionHandlers, s.eventHandler)
+			freeEventHandler(s.eventHandler)
+		}
+		s.conn.mu.Unlock()
+
+		s.eventHandler = nil
+	}
+
+	if code != 0 {
+		errs = append(errs, ErrorCode(code))
+	}
+
+	return errors.Join(errs...)
 }
 
 func (s *Session) QueryTimestamp(config string) (uint64, error) {
@@ -456,6 +658,8 @@ func (s *Session) RollbackTransaction(config string) error {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
+	s.txn = false
+
 	if code := int(C.wiredtiger_session_rollback_transaction(s.wtsession, configcstr)); code != 0 {
 		return ErrorCode(code)
 	}
@@ -662,6 +866,60 @@ func (s *Session) LogPrintf(format string, a ...any) error {
 	return nil
 }
 
+func (s *Session) Metadata(uri string) (string, error) {
+	metadata, err := s.OpenCursor("metadata:", "")
+	if err != nil {
+		return "", fmt.Errorf("open metadata cursor: %w", err)
+	}
+
+	defer metadata.Close()
+
+	if err := metadata.SetKey(uri); err != nil {
+		return "", fmt.Errorf("set metadata key: %w", err)
+	}
+
+	if err := metadata.Search(); err != nil {
+		return "", fmt.Errorf("search metadata for %s: %w", uri, err)
+	}
+
+	var config string
+
+	if err := metadata.GetValue(&config); err != nil {
+		return "", fmt.Errorf("get metadata value: %w", err)
+	}
+
+	return config, nil
+}
+
+func (s *Session) metadataKeys(prefix string) ([]string, error) {
+	metadata, err := s.OpenCursor("metadata:", "")
+	if err != nil {
+		return nil, fmt.Errorf("open metadata cursor: %w", err)
+	}
+
+	defer metadata.Close()
+
+	keys := make([]string, 0, 8)
+
+	for metadata.Next() {
+		var key string
+
+		if err := metadata.GetKey(&key); err != nil {
+			return nil, fmt.Errorf("get metadata key: %w", err)
+		}
+
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	if err := metadata.Err(); err != nil {
+		return nil, fmt.Errorf("iterate metadata: %w", err)
+	}
+
+	return keys, nil
+}
+
 func (s *Session) Reset() error {
 	if code := int(C.wiredtiger_session_reset(s.wtsession)); code != 0 {
 		return ErrorCode(code)
@@ -672,16 +930,36 @@ func (s *Session) Reset() error {
 
 type Cursor struct {
 	wtcursor *C.WT_CURSOR
+	session  *Session
 
 	keyPackers   []wtformat.FieldPacker
 	valuePackers []wtformat.FieldPacker
 
+	primaryKeyPackers []wtformat.FieldPacker
+
 	keybuf   []byte
 	valuebuf []byte
 	err      error
+
+	lower cursorBound
+	upper cursorBound
+
+	cacheKey *cursorCacheKey
+	inUse    bool
+
+	valueColumns []string
 }
 
 func (s *Session) OpenCursor(uri, config string) (*Cursor, error) {
+	return s.openCursor(uri, config, nil)
+}
+
+type cursorFormat struct {
+	keyPackers   []wtformat.FieldPacker
+	valuePackers []wtformat.FieldPacker
+}
+
+func (s *Session) openCursor(uri, config string, format *cursorFormat) (*Cursor, error) {
 	var wtcursor *C.WT_CURSOR
 	var dup *C.WT_CURSOR
 
@@ -701,22 +979,30 @@ func (s *Session) OpenCursor(uri, config string) (*Cursor, error) {
 		return nil, ErrorCode(code)
 	}
 
-	keyFormat := C.GoString(wtcursor.key_format)
-	keyPackers, err := wtformat.ParseFormat(keyFormat)
-	if err != nil {
-		return nil, fmt.Errorf("parse key format: %w", err)
-	}
+	if format == nil {
+		keyFormat := C.GoString(wtcursor.key_format)
+		keyPackers, err := wtformat.ParseFormat(keyFormat)
+		if err != nil {
+			return nil, fmt.Errorf("parse key format: %w", err)
+		}
 
-	valueFormat := C.GoString(wtcursor.value_format)
-	valuePackers, err := wtformat.ParseFormat(valueFormat)
-	if err != nil {
-		return nil, fmt.Errorf("parse value format: %w", err)
+		valueFormat := C.GoString(wtcursor.value_format)
+		valuePackers, err := wtformat.ParseFormat(valueFormat)
+		if err != nil {
+			return nil, fmt.Errorf("parse value format: %w", err)
+		}
+
+		format = &cursorFormat{
+			keyPackers:   keyPackers,
+			valuePackers: valuePackers,
+		}
 	}
 
 	cursor := &Cursor{
 		wtcursor:     wtcursor,
-		keyPackers:   keyPackers,
-		valuePackers: valuePackers,
+		session:      s,
+		keyPackers:   format.keyPackers,
+		valuePackers: format.valuePackers,
 	}
 
 	return cursor, nil
@@ -803,8 +1089,10 @@ func (c *Cursor) Equals(o *Cursor) (CursorEquality, error) {
 	return CursorEquality(compare), nil
 }
 
+// SetKey packs keys as the cursor's key, replacing any key set earlier
+// that no operation has consumed yet.
 func (c *Cursor) SetKey(keys ...any) error {
-	buf := c.keybuf
+	buf := c.keybuf[:0]
 
 	if len(keys) != len(c.keyPackers) {
 		return fmt.Errorf("number of keys does not match format")
@@ -824,7 +1112,54 @@ func (c *Cursor) SetKey(keys ...any) error {
 	return nil
 }
 
+type cursorBound struct {
+	key       []byte
+	inclusive bool
+	set       bool
+}
+
 func (c *Cursor) Bound(config string) error {
+	if err := c.bound(config, c.keybuf); err != nil {
+		return err
+	}
+
+	pairs, err := wtconfig.Parse(config)
+	if err != nil {
+		return fmt.Errorf("parse bound config: %w", err)
+	}
+
+	action, which, inclusive := "set", "", true
+
+	for _, p := range pairs {
+		switch p.Key {
+		case "action":
+			action = p.Value
+		case "bound":
+			which = p.Value
+		case "inclusive":
+			inclusive = p.Value == "true" || p.Value == "1"
+		}
+	}
+
+	b := cursorBound{
+		key:       append([]byte(nil), c.keybuf...),
+		inclusive: inclusive,
+		set:       action == "set",
+	}
+
+	switch {
+	case action == "clear" && which == "":
+		c.lower, c.upper = cursorBound{}, cursorBound{}
+	case which == "lower":
+		c.lower = b
+	case which == "upper":
+		c.upper = b
+	}
+
+	return nil
+}
+
+func (c *Cursor) bound(config string, key []byte) error {
 	var configcstr *C.char
 
 	if config != "" {
@@ -832,13 +1167,25 @@ func (c *Cursor) Bound(config string) error {
 		defer C.free(unsafe.Pointer(configcstr))
 	}
 
-	if code := int(C.wiredtiger_cursor_bound(c.wtcursor, configcstr)); code != 0 {
+	var packedkey unsafe.Pointer
+	var size C.size_t
+
+	if len(key) > 0 {
+		packedkey = unsafe.Pointer(&key[0])
+		size = C.size_t(len(key))
+	}
+
+	if code := int(C.wiredtiger_cursor_bound(c.wtcursor, configcstr, packedkey, size)); code != 0 {
 		return ErrorCode(code)
 	}
 
 	return nil
 }
 
+func (c *Cursor) URI() string {
+	return C.GoString(c.wtcursor.uri)
+}
+
 func (c *Cursor) ValueCount() int {
 	return len(c.valuePackers)
 }
@@ -847,8 +1194,10 @@ func (c *Cursor) KeyCount() int {
 	return len(c.keyPackers)
 }
 
+// SetValue packs values as the cursor's value, replacing any value set
+// earlier that no operation has consumed yet.
 func (c *Cursor) SetValue(values ...any) error {
-	buf := c.valuebuf
+	buf := c.valuebuf[:0]
 
 	if len(values) != len(c.valuePackers) {
 		return fmt.Errorf("number of values does not match format")
@@ -903,6 +1252,7 @@ func (c *Cursor) Reset() error {
 	c.keybuf = c.keybuf[:0]
 	c.valuebuf = c.valuebuf[:0]
 	c.err = nil
+	c.lower, c.upper = cursorBound{}, cursorBound{}
 
 	if code := int(C.wiredtiger_cursor_reset(c.wtcursor)); code != 0 {
 		return ErrorCode(code)
@@ -912,13 +1262,99 @@ func (c *Cursor) Reset() error {
 }
 
 func (c *Cursor) Reserve() error {
-	if code := int(C.wiredtiger_cursor_reserve(c.wtcursor)); code != 0 {
+	var packedkey unsafe.Pointer
+	var size C.size_t
+
+	if len(c.keybuf) > 0 {
+		packedkey = unsafe.Pointer(&c.keybuf[0])
+		size = C.size_t(len(c.keybuf))
+	}
+
+	if code := int(C.wiredtiger_cursor_reserve(c.wtcursor, packedkey, size)); code != 0 {
 		return ErrorCode(code)
 	}
 
 	return nil
 }
 
+type RetryOptions struct {
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+func (o RetryOptions) wait(start time.Time) bool {
+	if o.Timeout <= 0 || time.Since(start) >= o.Timeout {
+		return false
+	}
+
+	interval := o.Interval
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	time.Sleep(interval)
+
+	return true
+}
+
+func (c *Cursor) Lock(keys ...any) error {
+	return c.LockWithRetry(RetryOptions{}, keys...)
+}
+
+// LockWithRetry reserves the row at keys for the session's transaction. A
+// prepared update on the row is waited out within opts; a write conflict is
+// returned as ErrRollback and requires the transaction to be rolled back.
+func (c *Cursor) LockWithRetry(opts RetryOptions, keys ...any) error {
+	if !c.session.txn {
+		return ErrNoTransaction
+	}
+
+	if err := c.SetKey(keys...); err != nil {
+		return fmt.Errorf("set key: %w", err)
+	}
+
+	start := time.Now()
+
+	for {
+		err := c.Search()
+		if err == nil {
+			err = c.Reserve()
+		}
+
+		if errors.Is(err, ErrPrepareConflict) && opts.wait(start) {
+			continue
+		}
+
+		return err
+	}
+}
+
+func (s *Session) RunTransaction(config string, opts RetryOptions, fn func() error) error {
+	start := time.Now()
+
+	for {
+		if err := s.BeginTransaction(config); err != nil {
+			return fmt.Errorf("begin transaction: %w", err)
+		}
+
+		err := fn()
+		if err == nil {
+			err = s.CommitTransaction("")
+			if err == nil {
+				return nil
+			}
+		} else if rerr := s.RollbackTransaction(""); rerr != nil {
+			return errors.Join(err, fmt.Errorf("rollback transaction: %w", rerr))
+		}
+
+		if (errors.Is(err, ErrRollback) || errors.Is(err, ErrPrepareConflict)) && opts.wait(start) {
+			continue
+		}
+
+		return err
+	}
+}
+
 func (c *Cursor) Next() bool {
 	if code := int(C.wiredtiger_cursor_next(c.wtcursor)); code != 0 {
 		if ErrorCode(code) == ErrNotFound {
@@ -1052,6 +1488,311 @@ func (c *Cursor) Update() error {
 	return nil
 }
 
+type FieldUpdate struct {
+	Position int
+	Column   string
+	Value    any
+}
+
+// UpdateFields changes some fields of the value stored at the cursor's key
+// and leaves the others untouched. The changed fields are written in place
+// with Modify, and the whole value is rewritten only when WiredTiger cannot
+// modify the cursor's value format. Outside a transaction the update runs
+// in its own.
+func (c *Cursor) UpdateFields(updates ...FieldUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	positions := make([]int, len(updates))
+
+	for i, u := range updates {
+		p, err := c.valuePosition(u)
+		if err != nil {
+			return err
+		}
+
+		positions[i] = p
+	}
+
+	if c.session.txn {
+		return c.updateFields(positions, updates)
+	}
+
+	if err := c.session.BeginTransaction(""); err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := c.updateFields(positions, updates); err != nil {
+		if rerr := c.session.RollbackTransaction(""); rerr != nil {
+			return errors.Join(err, fmt.Errorf("rollback transaction: %w", rerr))
+		}
+
+		return err
+	}
+
+	if err := c.session.CommitTransaction(""); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cursor) updateFields(positions []int, updates []FieldUpdate) error {
+	if err := c.Search(); err != nil {
+		return fmt.Errorf("search: %w", err)
+	}
+
+	old, err := c.PackedValue()
+	if err != nil {
+		return fmt.Errorf("get value: %w", err)
+	}
+
+	offsets := make([]int, len(c.valuePackers)+1)
+	data := old
+
+	for i, p := range c.valuePackers {
+		var v any
+
+		d, err := p.UnpackField(data, &v)
+		if err != nil {
+			return fmt.Errorf("unpack field %d: %w", i, err)
+		}
+
+		offsets[i+1] = offsets[i] + len(data) - len(d)
+		data = d
+	}
+
+	fields := make([][]byte, len(c.valuePackers))
+
+	for i := range fields {
+		fields[i] = old[offsets[i]:offsets[i+1]]
+	}
+
+	changed := make([]bool, len(fields))
+
+	for i, u := range updates {
+		p := positions[i]
+
+		b, err := c.valuePackers[p].PackField(u.Value, nil)
+		if err != nil {
+			return fmt.Errorf("pack field %d: %w", p, err)
+		}
+
+		fields[p] = b
+		changed[p] = true
+	}
+
+	// Replace only the byte ranges of the changed fields. Entries are
+	// applied in order, so walk backwards to keep earlier offsets valid.
+	modifications := make([]Modification, 0, len(updates))
+
+	for i := len(fields) - 1; i >= 0; i-- {
+		if !changed[i] {
+			continue
+		}
+
+		m := Modification{
+			Data:   fields[i],
+			Offset: uint64(offsets[i]),
+			Size:   uint64(offsets[i+1] - offsets[i]),
+		}
+
+		modifications = append(modifications, m)
+	}
+
+	err = c.Modify(modifications)
+	if err == nil {
+		c.keybuf = c.keybuf[:0]
+		c.valuebuf = c.valuebuf[:0]
+
+		return nil
+	}
+
+	if !errors.Is(err, ErrorCode(syscall.ENOTSUP)) {
+		return fmt.Errorf("modify: %w", err)
+	}
+
+	value := c.valuebuf[:0]
+
+	for _, f := range fields {
+		value = append(value, f...)
+	}
+
+	c.valuebuf = value
+
+	if err := c.Update(); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Cursor) valuePosition(u FieldUpdate) (int, error) {
+	if u.Column == "" {
+		if u.Position < 0 || u.Position >= len(c.valuePackers) {
+			return 0, fmt.Errorf("value position %d out of range", u.Position)
+		}
+
+		return u.Position, nil
+	}
+
+	columns, err := c.ValueColumns()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, name := range columns {
+		if name == u.Column {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no value column named '%s'", u.Column)
+}
+
+func (c *Cursor) ValueColumns() ([]string, error) {
+	if c.valueColumns != nil {
+		return c.valueColumns, nil
+	}
+
+	uri := c.URI()
+
+	config, err := c.session.Metadata(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok, err := wtconfig.Get(config, "columns")
+	if err != nil {
+		return nil, fmt.Errorf("parse metadata: %w", err)
+	}
+
+	columns, err := wtconfig.List(value)
+	if err != nil {
+		return nil, fmt.Errorf("parse columns: %w", err)
+	}
+
+	if !ok || len(columns) != len(c.keyPackers)+len(c.valuePackers) {
+		return nil, fmt.Errorf("%s has no column names", uri)
+	}
+
+	c.valueColumns = columns[len(c.keyPackers):]
+
+	return c.valueColumns, nil
+}
+
+func (c *Cursor) PackedKey() ([]byte, error) {
+	var item C.WT_ITEM
+
+	if code := int(C.wiredtiger_cursor_get_key(c.wtcursor, &item)); code != 0 {
+		return nil, ErrorCode(code)
+	}
+
+	return C.GoBytes(unsafe.Pointer(item.data), C.int(item.size)), nil
+}
+
+func (c *Cursor) PackedValue() ([]byte, error) {
+	var item C.WT_ITEM
+
+	if code := int(C.wiredtiger_cursor_get_value(c.wtcursor, &item)); code != 0 {
+		return nil, ErrorCode(code)
+	}
+
+	return C.GoBytes(unsafe.Pointer(item.data), C.int(item.size)), nil
+}
+
+func (c *Cursor) SetPackedKey(key []byte) {
+	c.keybuf = append(c.keybuf[:0], key...)
+}
+
+func (c *Cursor) SetPackedValue(value []byte) {
+	c.valuebuf = append(c.valuebuf[:0], value...)
+}
+
+func (c *Cursor) CheckPackedKey(key []byte) error {
+	if err := checkPacked(c.keyPackers, key); err != nil {
+		return fmt.Errorf("key does not match format %s: %w", C.GoString(c.wtcursor.key_format), err)
+	}
+
+	return nil
+}
+
+func (c *Cursor) CheckPackedValue(value []byte) error {
+	if err := checkPacked(c.valuePackers, value); err != nil {
+		return fmt.Errorf("value does not match format %s: %w", C.GoString(c.wtcursor.value_format), err)
+	}
+
+	return nil
+}
+
+func checkPacked(packers []wtformat.FieldPacker, data []byte) error {
+	for i, p := range packers {
+		var v any
+
+		d, err := p.UnpackField(data, &v)
+		if err != nil {
+			return fmt.Errorf("unpack field %d: %w", i, err)
+		}
+
+		data = d
+	}
+
+	if len(data) != 0 {
+		return fmt.Errorf("%d trailing bytes", len(data))
+	}
+
+	return nil
+}
+
+// Pack encodes values in WiredTiger's packed form for format, the form
+// used by PackedKey, PackedValue and DataSource implementations.
+func Pack(format string, values ...any) ([]byte, error) {
+	packers, err := wtformat.ParseFormat(format)
+	if err != nil {
+		return nil, fmt.Errorf("parse format: %w", err)
+	}
+
+	if len(values) != len(packers) {
+		return nil, fmt.Errorf("number of values does not match format")
+	}
+
+	var buf []byte
+
+	for i, p := range packers {
+		b, err := p.PackField(values[i], buf)
+		if err != nil {
+			return nil, fmt.Errorf("pack field %d: %w", i, err)
+		}
+
+		buf = b
+	}
+
+	return buf, nil
+}
+
+func Unpack(format string, data []byte, values ...any) error {
+	packers, err := wtformat.ParseFormat(format)
+	if err != nil {
+		return fmt.Errorf("parse format: %w", err)
+	}
+
+	if len(values) != len(packers) {
+		return fmt.Errorf("number of values does not match format")
+	}
+
+	for i, p := range packers {
+		d, err := p.UnpackField(data, values[i])
+		if err != nil {
+			return fmt.Errorf("unpack field %d: %w", i, err)
+		}
+
+		data = d
+	}
+
+	return nil
+}
+
 type ErrorCode int16
 
 const (
@@ -1067,6 +1808,8 @@ const (
 	ErrTrySalvage      ErrorCode = -31809
 )
 
+var ErrNoTransaction = errors.New("no active transaction")
+
 func (err ErrorCode) Error() string {
 	return C.GoString(C.wiredtiger_strerror(C.int(err)))
 }
@@ -1,11 +1,14 @@
 package wtgo_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/dylrich/wtgo"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -77,6 +80,23 @@ func TestAll(t *testing.T) {
 	}
 }
 
+// Setting WTGO_TEST_FS=memory runs the tests against wtgo.MemFileSystem
+// instead of the operating system's file system.
+var testFileSystem = os.Getenv("WTGO_TEST_FS")
+
+// memFileSystems keeps one MemFileSystem per home directory so that tests
+// can close and reopen a database.
+var memFileSystems sync.Map
+
+func openTestConnection(dir, config string, opts wtgo.OpenOptions) (*wtgo.Connection, error) {
+	if testFileSystem == "memory" {
+		f, _ := memFileSystems.LoadOrStore(dir, wtgo.NewMemFileSystem())
+		opts.FileSystem = f.(*wtgo.MemFileSystem)
+	}
+
+	return wtgo.OpenWithOptions(dir, config, opts)
+}
+
 type tableCursorTestEnv struct {
 	conn    *wtgo.Connection
 	session *wtgo.Session
@@ -104,7 +124,7 @@ func newSessionTestEnv(connectionc, sessionc string) (*sessionTestEnv, error) {
 		return nil, fmt.Errorf("make temp dir: %w", err)
 	}
 
-	conn, err := wtgo.Open(dir, connectionc)
+	conn, err := openTestConnection(dir, connectionc, wtgo.OpenOptions{})
 	if err != nil {
 		os.RemoveAll(dir)
 		return nil, fmt.Errorf("open database: %s", err)
@@ -131,7 +151,7 @@ func newTableCursorTestEnv(connectionc, sessionc, tablename, tablec, cursorc str
 		return nil, fmt.Errorf("make temp dir: %w", err)
 	}
 
-	conn, err := wtgo.Open(dir, connectionc)
+	conn, err := openTestConnection(dir, connectionc, wtgo.OpenOptions{})
 	if err != nil {
 		os.RemoveAll(dir)
 		return nil, fmt.Errorf("open database: %s", err)
@@ -958,3 +978,551 @@ func TestCursorOperations(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdateFields(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=SQQS,columns=(id,name,hits,bytes,note)"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	records := []record{
+		{k: []any{"1"}, v: []any{"a", uint64(1), uint64(10), "first"}},
+		{k: []any{"2"}, v: []any{"b", uint64(2), uint64(20), "second"}},
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	columns, err := env.cursor.ValueColumns()
+	if err != nil {
+		t.Fatalf("value columns: %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"name", "hits", "bytes", "note"}, columns); diff != "" {
+		t.Fatalf("columns don't match (-want +got):\n%s", diff)
+	}
+
+	t.Run("outside-transaction", func(t *testing.T) {
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.UpdateFields(wtgo.FieldUpdate{Column: "hits", Value: uint64(1000)}); err != nil {
+			t.Fatalf("update fields: %s", err)
+		}
+
+		var name, note string
+		var hits, bytes uint64
+
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set search key: %s", err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		if err := env.cursor.GetValue(&name, &hits, &bytes, &note); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		if diff := cmp.Diff([]any{"a", uint64(1000), uint64(10), "first"}, []any{name, hits, bytes, note}); diff != "" {
+			t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("inside-transaction", func(t *testing.T) {
+		if err := env.session.BeginTransaction(""); err != nil {
+			t.Fatalf("begin transaction: %s", err)
+		}
+
+		if err := env.cursor.SetKey("2"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		updates := []wtgo.FieldUpdate{
+			{Position: 3, Value: "a much longer note"},
+			{Column: "name", Value: "bb"},
+		}
+
+		if err := env.cursor.UpdateFields(updates...); err != nil {
+			t.Fatalf("update fields: %s", err)
+		}
+
+		if err := env.session.RollbackTransaction(""); err != nil {
+			t.Fatalf("rollback transaction: %s", err)
+		}
+
+		var name, note string
+		var hits, bytes uint64
+
+		if err := env.cursor.SetKey("2"); err != nil {
+			t.Fatalf("set search key: %s", err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		if err := env.cursor.GetValue(&name, &hits, &bytes, &note); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		if diff := cmp.Diff([]any{"b", uint64(2), uint64(20), "second"}, []any{name, hits, bytes, note}); diff != "" {
+			t.Fatalf("value changed after rollback (-want +got):\n%s", diff)
+		}
+
+		if err := env.session.BeginTransaction(""); err != nil {
+			t.Fatalf("begin transaction: %s", err)
+		}
+
+		if err := env.cursor.SetKey("2"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.UpdateFields(updates...); err != nil {
+			t.Fatalf("update fields: %s", err)
+		}
+
+		if err := env.session.CommitTransaction(""); err != nil {
+			t.Fatalf("commit transaction: %s", err)
+		}
+
+		if err := env.cursor.SetKey("2"); err != nil {
+			t.Fatalf("set search key: %s", err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		if err := env.cursor.GetValue(&name, &hits, &bytes, &note); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		if diff := cmp.Diff([]any{"bb", uint64(2), uint64(20), "a much longer note"}, []any{name, hits, bytes, note}); diff != "" {
+			t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("untouched-fields", func(t *testing.T) {
+		packedValue := func() []byte {
+			if err := env.cursor.SetKey("1"); err != nil {
+				t.Fatalf("set search key: %s", err)
+			}
+
+			if err := env.cursor.Search(); err != nil {
+				t.Fatalf("search: %s", err)
+			}
+
+			value, err := env.cursor.PackedValue()
+			if err != nil {
+				t.Fatalf("packed value: %s", err)
+			}
+
+			return bytes.Clone(value)
+		}
+
+		before := packedValue()
+
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.UpdateFields(wtgo.FieldUpdate{Column: "hits", Value: uint64(5)}); err != nil {
+			t.Fatalf("update fields: %s", err)
+		}
+
+		after := packedValue()
+
+		name, err := wtgo.Pack("S", "a")
+		if err != nil {
+			t.Fatalf("pack name: %s", err)
+		}
+
+		oldHits, err := wtgo.Pack("Q", uint64(1000))
+		if err != nil {
+			t.Fatalf("pack old hits: %s", err)
+		}
+
+		newHits, err := wtgo.Pack("Q", uint64(5))
+		if err != nil {
+			t.Fatalf("pack new hits: %s", err)
+		}
+
+		if diff := cmp.Diff(before[:len(name)], after[:len(name)]); diff != "" {
+			t.Fatalf("fields before the update changed (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff(newHits, after[len(name):len(name)+len(newHits)]); diff != "" {
+			t.Fatalf("updated field doesn't match (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff(before[len(name)+len(oldHits):], after[len(name)+len(newHits):]); diff != "" {
+			t.Fatalf("fields after the update changed (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("unknown-column", func(t *testing.T) {
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.UpdateFields(wtgo.FieldUpdate{Column: "missing", Value: uint64(1)}); err == nil {
+			t.Fatalf("expected error for unknown column")
+		}
+	})
+}
+
+func TestUpdateFieldsMultiColumn(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=SQS,columns=(id,name,hits,note)"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := seed(env.cursor, []record{{k: []any{"1"}, v: []any{"name", uint64(1), "note"}}}); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	packedValue := func() []byte {
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set search key: %s", err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search: %s", err)
+		}
+
+		value, err := env.cursor.PackedValue()
+		if err != nil {
+			t.Fatalf("packed value: %s", err)
+		}
+
+		return bytes.Clone(value)
+	}
+
+	cases := []struct {
+		update wtgo.FieldUpdate
+		want   []any
+	}{
+		{update: wtgo.FieldUpdate{Column: "hits", Value: uint64(100000)}, want: []any{"name", uint64(100000), "note"}},
+		{update: wtgo.FieldUpdate{Column: "name", Value: "a longer name"}, want: []any{"a longer name", uint64(100000), "note"}},
+		{update: wtgo.FieldUpdate{Column: "note", Value: "n"}, want: []any{"a longer name", uint64(100000), "n"}},
+	}
+
+	for _, tc := range cases {
+		want, err := wtgo.Pack("SQS", tc.want...)
+		if err != nil {
+			t.Fatalf("pack %v: %s", tc.want, err)
+		}
+
+		if err := env.cursor.SetKey("1"); err != nil {
+			t.Fatalf("set key: %s", err)
+		}
+
+		if err := env.cursor.UpdateFields(tc.update); err != nil {
+			t.Fatalf("update %s: %s", tc.update.Column, err)
+		}
+
+		if diff := cmp.Diff(want, packedValue()); diff != "" {
+			t.Fatalf("value after updating %s doesn't match (-want +got):\n%s", tc.update.Column, diff)
+		}
+	}
+}
+
+func TestPackedKeyValue(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=SQ,value_format=SqS"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	records := []record{
+		{k: []any{"a", uint64(1)}, v: []any{"x", int64(-1), "first"}},
+		{k: []any{"b", uint64(2)}, v: []any{"y", int64(0), "second"}},
+		{k: []any{"c", uint64(3)}, v: []any{"z", int64(1), "third"}},
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	copyname := "table:test-table-copy"
+
+	if err := env.session.Create(copyname, tableconf); err != nil {
+		t.Fatalf("create copy: %s", err)
+	}
+
+	dst, err := env.session.OpenCursor(copyname, "")
+	if err != nil {
+		t.Fatalf("open copy cursor: %s", err)
+	}
+
+	for env.cursor.Next() {
+		key, err := env.cursor.PackedKey()
+		if err != nil {
+			t.Fatalf("packed key: %s", err)
+		}
+
+		value, err := env.cursor.PackedValue()
+		if err != nil {
+			t.Fatalf("packed value: %s", err)
+		}
+
+		if err := dst.CheckPackedKey(key); err != nil {
+			t.Fatalf("check packed key: %s", err)
+		}
+
+		if err := dst.CheckPackedValue(value); err != nil {
+			t.Fatalf("check packed value: %s", err)
+		}
+
+		dst.SetPackedKey(key)
+		dst.SetPackedValue(value)
+
+		if err := dst.Insert(); err != nil {
+			t.Fatalf("insert: %s", err)
+		}
+	}
+
+	if err := env.cursor.Err(); err != nil {
+		t.Fatalf("iteration: %s", err)
+	}
+
+	if err := dst.Reset(); err != nil {
+		t.Fatalf("reset copy cursor: %s", err)
+	}
+
+	for i := 0; dst.Next(); i++ {
+		var k1 string
+		var k2 uint64
+		var v1, v3 string
+		var v2 int64
+
+		if err := dst.GetKey(&k1, &k2); err != nil {
+			t.Fatalf("get key: %s", err)
+		}
+
+		if err := dst.GetValue(&v1, &v2, &v3); err != nil {
+			t.Fatalf("get value: %s", err)
+		}
+
+		want := records[i]
+
+		if diff := cmp.Diff(want.k, []any{k1, k2}); diff != "" {
+			t.Fatalf("key doesn't match (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff(want.v, []any{v1, v2, v3}); diff != "" {
+			t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+		}
+	}
+
+	if err := dst.Err(); err != nil {
+		t.Fatalf("copy iteration: %s", err)
+	}
+
+	cases := map[string]struct {
+		check func([]byte) error
+		data  []byte
+	}{
+		"key-truncated": {
+			check: dst.CheckPackedKey,
+			data:  []byte("a"),
+		},
+		"key-trailing-bytes": {
+			check: dst.CheckPackedKey,
+			data:  []byte("a\x00\x81\x81"),
+		},
+		"value-missing-field": {
+			check: dst.CheckPackedValue,
+			data:  []byte("x\x00\x80"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.check(tc.data); err == nil {
+				t.Fatalf("expected check of %q to fail", tc.data)
+			}
+		})
+	}
+}
+
+// SetKey and SetValue replace what was set before rather than appending to
+// it. Search and Reserve keep the key set, so locking two rows in turn with
+// the same cursor used to search for both keys packed together.
+func TestSetKeyReplacesKey(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	if err := seed(env.cursor, []record{{k: []any{"a"}, v: []any{"1"}}, {k: []any{"b"}, v: []any{"2"}}}); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	for _, k := range []string{"a", "b"} {
+		if err := env.cursor.SetKey(k); err != nil {
+			t.Fatalf("set key %s: %s", k, err)
+		}
+
+		if err := env.cursor.Search(); err != nil {
+			t.Fatalf("search %s after an earlier search: %s", k, err)
+		}
+	}
+
+	if err := env.cursor.SetKey("c"); err != nil {
+		t.Fatalf("set key: %s", err)
+	}
+
+	for _, v := range []string{"stale", "3"} {
+		if err := env.cursor.SetValue(v); err != nil {
+			t.Fatalf("set value %s: %s", v, err)
+		}
+	}
+
+	if err := env.cursor.Insert(); err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	r, err := searchKey[string, string](env.cursor, "c")
+	if err != nil {
+		t.Fatalf("search c: %s", err)
+	}
+
+	if r.Value != "3" {
+		t.Fatalf("value is '%s', expected '3'", r.Value)
+	}
+}
+
+func TestLock(t *testing.T) {
+	tablename := "table:test-table"
+	tableconf := "key_format=S,value_format=S"
+
+	env, err := newTableCursorTestEnv("create", "", tablename, tableconf, "")
+	if err != nil {
+		t.Fatalf("new table cursor test env: %s", err)
+	}
+
+	t.Cleanup(func() { env.Close() })
+
+	records := []record{
+		{k: []any{"1"}, v: []any{"a"}},
+		{k: []any{"2"}, v: []any{"b"}},
+	}
+
+	if err := seed(env.cursor, records); err != nil {
+		t.Fatalf("seed database: %s", err)
+	}
+
+	other, err := env.conn.OpenSession("")
+	if err != nil {
+		t.Fatalf("open other session: %s", err)
+	}
+
+	otherCursor, err := other.OpenCursor(tablename, "")
+	if err != nil {
+		t.Fatalf("open other cursor: %s", err)
+	}
+
+	if err := env.cursor.Lock("1"); !errors.Is(err, wtgo.ErrNoTransaction) {
+		t.Fatalf("lock outside transaction returned err '%s', expected no transaction", err)
+	}
+
+	if err := env.session.BeginTransaction(""); err != nil {
+		t.Fatalf("begin transaction: %s", err)
+	}
+
+	if err := env.cursor.Lock("1"); err != nil {
+		t.Fatalf("lock: %s", err)
+	}
+
+	if err := env.cursor.Lock("3"); !errors.Is(err, wtgo.ErrNotFound) {
+		t.Fatalf("lock missing key returned err '%s', expected not found", err)
+	}
+
+	if err := other.BeginTransaction(""); err != nil {
+		t.Fatalf("begin other transaction: %s", err)
+	}
+
+	if err := otherCursor.Lock("1"); !errors.Is(err, wtgo.ErrRollback) {
+		t.Fatalf("lock of reserved row returned err '%s', expected rollback", err)
+	}
+
+	if err := other.RollbackTransaction(""); err != nil {
+		t.Fatalf("rollback other transaction: %s", err)
+	}
+
+	released := make(chan error, 1)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		released <- env.session.CommitTransaction("")
+	}()
+
+	opts := wtgo.RetryOptions{
+		Timeout:  5 * time.Second,
+		Interval: 10 * time.Millisecond,
+	}
+
+	var attempts int
+
+	err = other.RunTransaction("", opts, func() error {
+		attempts++
+
+		if err := otherCursor.Lock("1"); err != nil {
+			return err
+		}
+
+		if err := otherCursor.SetKey("1"); err != nil {
+			return fmt.Errorf("set key: %w", err)
+		}
+
+		if err := otherCursor.SetValue("c"); err != nil {
+			return fmt.Errorf("set value: %w", err)
+		}
+
+		return otherCursor.Update()
+	})
+	if err != nil {
+		t.Fatalf("run transaction: %s", err)
+	}
+
+	if err := <-released; err != nil {
+		t.Fatalf("commit transaction: %s", err)
+	}
+
+	if attempts < 2 {
+		t.Fatalf("expected contended lock to retry, got %d attempts", attempts)
+	}
+
+	r, err := searchKey[string, string](env.cursor, "1")
+	if err != nil {
+		t.Fatalf("search key: %s", err)
+	}
+
+	if diff := cmp.Diff("c", r.Value); diff != "" {
+		t.Fatalf("value doesn't match (-want +got):\n%s", diff)
+	}
+}